@@ -0,0 +1,83 @@
+package micrograd
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Activation is the nonlinearity a Neuron applies to its weighted sum.
+// The Name identifies the activation when a network is saved and loaded.
+type Activation struct {
+	Name string
+	Fn   func(*Value) *Value
+}
+
+var (
+	Identity = Activation{Name: "identity", Fn: func(v *Value) *Value { return v }}
+	ReLU     = Activation{Name: "relu", Fn: (*Value).Relu}
+	Tanh     = Activation{Name: "tanh", Fn: (*Value).Tanh}
+	Sigmoid  = Activation{Name: "sigmoid", Fn: (*Value).Sigmoid}
+	GELU     = Activation{Name: "gelu", Fn: (*Value).Gelu}
+	SiLU     = Activation{Name: "silu", Fn: (*Value).Silu}
+)
+
+// LeakyReLU returns a ReLU that lets through alpha times the negative inputs
+func LeakyReLU(alpha float64) Activation {
+	return Activation{
+		Name: fmt.Sprintf("leaky_relu(%v)", alpha),
+		Fn:   func(v *Value) *Value { return v.LeakyRelu(alpha) },
+	}
+}
+
+// CustomActivation wraps a user supplied function and registers it under name,
+// so that networks using it can be loaded again with LoadMLP
+func CustomActivation(name string, fn func(*Value) *Value) Activation {
+	a := Activation{Name: name, Fn: fn}
+	activationsMu.Lock()
+	defer activationsMu.Unlock()
+	activations[name] = a
+	return a
+}
+
+// Apply runs the activation on v; the zero Activation is the identity
+func (a Activation) Apply(v *Value) *Value {
+	if a.Fn == nil {
+		return v
+	}
+	return a.Fn(v)
+}
+
+func (a Activation) String() string {
+	if a.Name == "" {
+		return Identity.Name
+	}
+	return a.Name
+}
+
+var (
+	activationsMu sync.RWMutex
+	activations   = map[string]Activation{}
+)
+
+func init() {
+	for _, a := range []Activation{Identity, ReLU, Tanh, Sigmoid, GELU, SiLU} {
+		activations[a.Name] = a
+	}
+}
+
+// ActivationByName finds a built in or registered custom activation
+func ActivationByName(name string) (Activation, error) {
+	if name == "" {
+		return Identity, nil
+	}
+	var alpha float64
+	if _, err := fmt.Sscanf(name, "leaky_relu(%g)", &alpha); err == nil {
+		return LeakyReLU(alpha), nil
+	}
+	activationsMu.RLock()
+	defer activationsMu.RUnlock()
+	if a, ok := activations[name]; ok {
+		return a, nil
+	}
+	return Activation{}, fmt.Errorf("unknown activation %q", name)
+}
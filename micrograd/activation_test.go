@@ -0,0 +1,60 @@
+package micrograd
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMLPActivations(t *testing.T) {
+	mlp := NewMLP(2, []int{8, 8, 1}, WithHiddenActivation(Tanh), WithOutputActivation(Sigmoid))
+	for i, layer := range mlp.layers {
+		want := Tanh.Name
+		if i == len(mlp.layers)-1 {
+			want = Sigmoid.Name
+		}
+		if got := layer.activation().Name; got != want {
+			t.Errorf("layer %d: got activation %s, want %s", i, got, want)
+		}
+	}
+	out := mlp.Forward([]*Value{NewValue(3.0), NewValue(-2.0)})[0]
+	if out.Data <= 0 || out.Data >= 1 {
+		t.Errorf("sigmoid head output %v outside (0, 1)", out.Data)
+	}
+}
+
+func TestMLPDefaultActivations(t *testing.T) {
+	mlp := NewMLP(2, []int{3, 3, 1})
+	want := []string{"relu", "relu", "identity"}
+	for i, layer := range mlp.layers {
+		if got := layer.activation().String(); got != want[i] {
+			t.Errorf("layer %d: got activation %s, want %s", i, got, want[i])
+		}
+	}
+}
+
+func TestWithActivationsPerLayer(t *testing.T) {
+	square := CustomActivation("square", func(v *Value) *Value { return v.Mul(v) })
+	mlp := NewMLP(2, []int{3, 3, 2}, WithActivations(LeakyReLU(0.1), square, Identity))
+
+	data, err := json.Marshal(mlp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := &MLP{}
+	if err := json.Unmarshal(data, loaded); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"leaky_relu(0.1)", "square", "identity"}
+	for i, layer := range loaded.layers {
+		if got := layer.activation().Name; got != want[i] {
+			t.Errorf("layer %d: got activation %s, want %s", i, got, want[i])
+		}
+	}
+	checkSameForward(t, mlp, loaded)
+}
+
+func TestActivationByNameUnknown(t *testing.T) {
+	if _, err := ActivationByName("no-such-activation"); err == nil {
+		t.Error("expected an error for an unknown activation")
+	}
+}
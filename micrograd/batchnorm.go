@@ -0,0 +1,140 @@
+package micrograd
+
+import (
+	"fmt"
+	"math"
+)
+
+// BatchNorm normalizes every feature over a batch of examples to zero mean and
+// unit variance, then scales it by Gamma and shifts it by Beta, both trained.
+// While training it uses the statistics of the batch and keeps running averages
+// of them, which evaluation mode uses instead.
+type BatchNorm struct {
+	Gamma, Beta []*Value
+	// RunningMean and RunningVar are the averages of the batch statistics, updated
+	// with RunningMean = (1-Momentum)*RunningMean + Momentum*mean after every batch
+	RunningMean, RunningVar []float64
+	Momentum                float64
+	// Eps is added to the variance before dividing by its square root
+	Eps  float64
+	eval bool
+}
+
+// NewBatchNorm normalizes n features, starting from Gamma 1, Beta 0, a
+// running mean of 0 and a running variance of 1
+func NewBatchNorm(n int) *BatchNorm {
+	b := &BatchNorm{
+		Gamma:       make([]*Value, n),
+		Beta:        make([]*Value, n),
+		RunningMean: make([]float64, n),
+		RunningVar:  make([]float64, n),
+		Momentum:    0.1,
+		Eps:         1e-5,
+	}
+	for i := 0; i < n; i++ {
+		b.Gamma[i] = NewValue(1.0)
+		b.Beta[i] = NewValue(0.0)
+		b.RunningVar[i] = 1.0
+	}
+	return b
+}
+
+// ForwardBatch normalizes xs, one row of features per example. In training
+// mode it needs at least two examples.
+func (b *BatchNorm) ForwardBatch(xs [][]*Value) [][]*Value {
+	out := make([][]*Value, len(xs))
+	for i, x := range xs {
+		if len(x) != len(b.Gamma) {
+			panic("input size mismatch")
+		}
+		out[i] = make([]*Value, len(x))
+	}
+	if b.eval {
+		for i, x := range xs {
+			out[i] = b.Forward(x)
+		}
+		return out
+	}
+	if len(xs) < 2 {
+		panic("batch norm needs at least two examples per batch while training")
+	}
+
+	n := float64(len(xs))
+	column := make([]*Value, len(xs))
+	for j := range b.Gamma {
+		for i := range xs {
+			column[i] = xs[i][j]
+		}
+		mean := Mean(column...)
+		for i := range xs {
+			column[i] = xs[i][j].Sub(mean).Pow(2)
+		}
+		variance := Mean(column...)
+		invStd := variance.Add(Constant(b.Eps)).Pow(-0.5)
+		for i := range xs {
+			out[i][j] = xs[i][j].Sub(mean).Mul(invStd).Mul(b.Gamma[j]).Add(b.Beta[j])
+		}
+		// the running variance is unbiased, like in PyTorch
+		b.RunningMean[j] = (1-b.Momentum)*b.RunningMean[j] + b.Momentum*mean.Data
+		b.RunningVar[j] = (1-b.Momentum)*b.RunningVar[j] + b.Momentum*variance.Data*n/(n-1)
+	}
+	return out
+}
+
+// Forward normalizes a single example with the running statistics, whatever the mode
+func (b *BatchNorm) Forward(x []*Value) []*Value {
+	if len(x) != len(b.Gamma) {
+		panic("input size mismatch")
+	}
+	out := make([]*Value, len(x))
+	for j := range x {
+		invStd := 1 / math.Sqrt(b.RunningVar[j]+b.Eps)
+		out[j] = x[j].Sub(Constant(b.RunningMean[j])).Mul(Constant(invStd)).Mul(b.Gamma[j]).Add(b.Beta[j])
+	}
+	return out
+}
+
+func (b *BatchNorm) Parameters() []*Value {
+	return append(append([]*Value{}, b.Gamma...), b.Beta...)
+}
+
+// NamedParameters names the scales "gamma.<feature>" and the shifts "beta.<feature>"
+func (b *BatchNorm) NamedParameters() []NamedParameter {
+	out := make([]NamedParameter, 0, 2*len(b.Gamma))
+	for i, g := range b.Gamma {
+		out = append(out, NamedParameter{fmt.Sprintf("gamma.%d", i), g})
+	}
+	for i, v := range b.Beta {
+		out = append(out, NamedParameter{fmt.Sprintf("beta.%d", i), v})
+	}
+	return out
+}
+
+// namedBuffers names the running statistics "running_mean.<feature>" and "running_var.<feature>"
+func (b *BatchNorm) namedBuffers() []namedBuffer {
+	out := make([]namedBuffer, 0, 2*len(b.Gamma))
+	for i := range b.RunningMean {
+		out = append(out, namedBuffer{fmt.Sprintf("running_mean.%d", i), &b.RunningMean[i]})
+	}
+	for i := range b.RunningVar {
+		out = append(out, namedBuffer{fmt.Sprintf("running_var.%d", i), &b.RunningVar[i]})
+	}
+	return out
+}
+
+func (b *BatchNorm) ZeroGrad() {
+	zeroGrad(b.Parameters())
+}
+
+// SetTraining switches between training (batch statistics) and evaluation mode (running statistics)
+func (b *BatchNorm) SetTraining(training bool) {
+	b.eval = !training
+}
+
+func (b *BatchNorm) Training() bool {
+	return !b.eval
+}
+
+func (b *BatchNorm) String() string {
+	return fmt.Sprintf("BatchNorm(%d)", len(b.Gamma))
+}
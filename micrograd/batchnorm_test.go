@@ -0,0 +1,80 @@
+package micrograd
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBatchNormTraining(t *testing.T) {
+	b := NewBatchNorm(2)
+	b.Momentum = 0.5
+	xs := [][]*Value{
+		{NewValue(1), NewValue(10)},
+		{NewValue(2), NewValue(20)},
+		{NewValue(3), NewValue(60)},
+	}
+	out := b.ForwardBatch(xs)
+	for j := 0; j < 2; j++ {
+		mean, sq := 0.0, 0.0
+		for i := range out {
+			mean += out[i][j].Data / 3
+			sq += out[i][j].Data * out[i][j].Data / 3
+		}
+		if math.Abs(mean) > 1e-12 || math.Abs(sq-1) > 1e-4 {
+			t.Errorf("feature %d: mean %v and variance %v, want 0 and 1", j, mean, sq-mean*mean)
+		}
+	}
+	// batch mean 2, unbiased variance 1
+	if b.RunningMean[0] != 1 || math.Abs(b.RunningVar[0]-1) > 1e-12 {
+		t.Errorf("running mean %v and variance %v, want 1 and 1", b.RunningMean[0], b.RunningVar[0])
+	}
+
+	b.SetTraining(false)
+	got := b.ForwardBatch(xs)[2][0].Data
+	if want := (3 - 1) / math.Sqrt(1+b.Eps); math.Abs(got-want) > 1e-12 {
+		t.Errorf("evaluation output %v, want %v", got, want)
+	}
+}
+
+func TestBatchNormGradients(t *testing.T) {
+	b := NewBatchNorm(1)
+	b.Gamma[0].Data, b.Beta[0].Data = 1.5, -0.3
+	results := GradCheck(func(v []*Value) *Value {
+		out := b.ForwardBatch([][]*Value{{v[0]}, {v[1]}, {v[2]}})
+		return out[0][0].Mul(out[1][0]).Add(out[2][0].Tanh())
+	}, []float64{0.3, -1.2, 0.8}, 1e-6)
+	if err := MaxRelError(results); err > 1e-5 {
+		t.Errorf("max relative error %v: %v", err, results)
+	}
+}
+
+func TestMLPWithRegularization(t *testing.T) {
+	mlp := NewMLP(2, []int{4, 4, 1}, WithSeed(1), WithBatchNorm(), WithDropout(0.5))
+	// the output layer is not regularized
+	if n := len(mlp.Parameters()); n != 12+20+5+2*8 {
+		t.Errorf("got %d parameters, want %d", n, 12+20+5+2*8)
+	}
+	if mlp.Compilable() {
+		t.Error("a training network with dropout should not be compilable")
+	}
+	xs := [][]*Value{{NewValue(0.5), NewValue(-1)}, {NewValue(1), NewValue(0.2)}, {NewValue(-0.3), NewValue(0.7)}}
+	out := mlp.ForwardBatch(xs)
+	if len(out) != 3 || len(out[0]) != 1 {
+		t.Fatalf("got %dx%d outputs, want 3x1", len(out), len(out[0]))
+	}
+	Sum(out[0][0], out[1][0], out[2][0]).Backward()
+	if g := mlp.layers[0].norm.Gamma; g[0].Grad == 0 && g[1].Grad == 0 && g[2].Grad == 0 && g[3].Grad == 0 {
+		t.Error("batch norm parameters got no gradient")
+	}
+
+	mlp.SetTraining(false)
+	if !mlp.Compilable() || mlp.layers[1].dropout.Training() {
+		t.Error("SetTraining(false) did not reach the layers")
+	}
+	a := mlp.ForwardBatch(xs)
+	for i := range xs {
+		if b := mlp.Forward(xs[i]); b[0].Data != a[i][0].Data {
+			t.Errorf("example %d: Forward %v, ForwardBatch %v in evaluation mode", i, b[0].Data, a[i][0].Data)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package micrograd
+
+import "errors"
+
+// Clone makes a deep copy of the graph rooted at v: every value, leaves
+// included, is new, with the same Data and a zero Grad, and computed by the same
+// ops. Changing or training the copy leaves the original as it is.
+// Values computed without a graph (see NoGradValue) cannot be cloned.
+func (v *Value) Clone() (*Value, error) {
+	clone, _, err := CloneGraph(v)
+	return clone, err
+}
+
+// CloneGraph is Clone, also returning the copy of every value of the graph by
+// original, so that e.g. the copies of the parameters can be found
+func CloneGraph(root *Value) (*Value, map[*Value]*Value, error) {
+	if root.nograd {
+		return nil, nil, errors.New("cannot clone a value computed without gradients")
+	}
+	topo := root.topo()
+	clones := make(map[*Value]*Value, len(topo))
+	for _, v := range topo {
+		if len(v.prev) == 0 {
+			leaf := NewValue(v.Data)
+			leaf.constant, leaf.frozen, leaf.dataDependent = v.constant, v.frozen, v.dataDependent
+			clones[v] = leaf
+			continue
+		}
+		children := make([]*Value, len(v.prev))
+		for i, child := range v.prev {
+			children[i] = clones[child]
+		}
+		code, err := opcodeOf(v)
+		if err != nil {
+			return nil, nil, err
+		}
+		clone, err := rebuild(code, v.arg, children)
+		if err != nil {
+			return nil, nil, err
+		}
+		clones[v] = clone
+	}
+	return clones[root], clones, nil
+}
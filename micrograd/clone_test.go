@@ -0,0 +1,122 @@
+package micrograd
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDetach(t *testing.T) {
+	x := NewValue(3.0)
+	y := x.Mul(x)
+	d := y.Detach()
+	z := d.Mul(x)
+	z.Backward()
+	if d.Data != 9 || len(d.prev) != 0 {
+		t.Errorf("detached value %v has children %v", d, d.prev)
+	}
+	// only the direct use of x gets a gradient
+	if x.Grad != 9 {
+		t.Errorf("x.Grad = %v, want 9", x.Grad)
+	}
+}
+
+func TestStopGradient(t *testing.T) {
+	x := NewValue(1.3)
+	// straight-through rounding: forward rounds, backward acts as the identity
+	rounded := x.Add(NewValue(math.Round(x.Data) - x.Data).StopGradient())
+	y := rounded.Mul(rounded).Add(x.Exp().StopGradient())
+	y.Backward()
+	if y.Data != 1+math.Exp(1.3) {
+		t.Errorf("y = %v, want %v", y.Data, 1+math.Exp(1.3))
+	}
+	if x.Grad != 2 {
+		t.Errorf("x.Grad = %v, want 2", x.Grad)
+	}
+
+	grads, err := Gradients(y, x)
+	if err != nil || grads[0].Data != 2 {
+		t.Errorf("Gradients = %v, %v, want 2", grads, err)
+	}
+
+	tape, err := Compile(y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x.Data, x.Grad = 2.2, 0
+	tape.Forward()
+	tape.Backward()
+	// the rounding offset is a leaf, the tape reuses it
+	if want := 1.9*1.9 + math.Exp(2.2); math.Abs(y.Data-want) > 1e-12 {
+		t.Errorf("tape y = %v, want %v", y.Data, want)
+	}
+	if x.Grad != 2*rounded.Data {
+		t.Errorf("tape x.Grad = %v, want %v", x.Grad, 2*rounded.Data)
+	}
+
+	if _, jv := JVP(func(v []Dual) []Dual { return []Dual{v[0].Mul(v[0].StopGradient())} }, []float64{3}, []float64{1}); jv[0] != 3 {
+		t.Errorf("forward mode derivative %v, want 3", jv[0])
+	}
+}
+
+func TestCloneIsIndependent(t *testing.T) {
+	x := []*Value{NewValue(0.7), NewValue(-1.3), NewValue(2.1)}
+	f := everyOp(x)
+	clone, copies, err := CloneGraph(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clone.Data != f.Data || len(clone.topo()) != len(f.topo()) {
+		t.Fatalf("clone computes %v with %d values, want %v with %d", clone.Data, len(clone.topo()), f.Data, len(f.topo()))
+	}
+
+	clone.Backward()
+	f.Backward()
+	for i := range x {
+		c := copies[x[i]]
+		if c == x[i] || c.Grad != x[i].Grad {
+			t.Errorf("input %d: clone grad %v, original %v", i, c.Grad, x[i].Grad)
+		}
+	}
+	for _, v := range clone.topo() {
+		if _, shared := copies[v]; shared {
+			t.Fatal("clone shares values with the original")
+		}
+	}
+
+	copies[x[0]].Data = 5
+	tape, err := Compile(clone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tape.Forward() == f.Data || f.Data != everyOp([]*Value{NewValue(0.7), NewValue(-1.3), NewValue(2.1)}).Data {
+		t.Error("changing the clone changed the original")
+	}
+}
+
+func TestCloneErrors(t *testing.T) {
+	x := NewValue(0.5)
+	inference := NoGradValue(0.5)
+	if _, err := x.Mul(inference).Tanh().Clone(); err == nil {
+		t.Error("expected an error when cloning a value computed without gradients")
+	}
+	unknown := &Value{Data: 1, prev: []*Value{x}, op: "custom"}
+	if _, _, err := CloneGraph(unknown.Add(x)); err == nil {
+		t.Error("expected an error for an op that cannot be rebuilt")
+	}
+}
+
+func TestMLPClone(t *testing.T) {
+	mlp := NewMLP(2, []int{4, 3, 2}, WithSeed(6), WithBatchNorm())
+	Freeze(mlp, "layers.2")
+	target := mlp.Clone()
+	checkSameForward(t, mlp, target)
+	if target.String() != mlp.String() || len(TrainableParameters(target)) != len(TrainableParameters(mlp)) {
+		t.Errorf("clone %v differs from %v", target, mlp)
+	}
+	for _, p := range target.Parameters() {
+		p.Data += 1
+	}
+	if mlp.layers[0].neurons[0].w[0].Data == target.layers[0].neurons[0].w[0].Data {
+		t.Error("the clone shares parameters with the original")
+	}
+}
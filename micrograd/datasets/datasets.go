@@ -0,0 +1,173 @@
+// Package datasets generates seeded synthetic 2D classification datasets
+package datasets
+
+import (
+	"math"
+	"math/rand"
+	"vdanciu_lang_model/micrograd/migete"
+)
+
+type Config struct {
+	// Samples is the total number of points (defaults to 100)
+	Samples int
+	// Noise is the standard deviation of the Gaussian noise added to every point
+	Noise float64
+	// Classes is the number of classes for the generators that support more than 2
+	// (Circles, Blobs and Spirals), it defaults to 2
+	Classes int
+	Seed    int64
+}
+
+// Dataset holds 2D points and their class labels in 0..Classes-1
+type Dataset struct {
+	X       [][]float64
+	Y       []int
+	Classes int
+}
+
+func (d *Dataset) Len() int {
+	return len(d.X)
+}
+
+// Targets returns the labels as floats, as used by train.Dataset
+func (d *Dataset) Targets() []float64 {
+	out := make([]float64, len(d.Y))
+	for i, y := range d.Y {
+		out[i] = float64(y)
+	}
+	return out
+}
+
+// Signed returns the labels of a 2 class dataset mapped to {-1, 1}, as used by the hinge loss
+func (d *Dataset) Signed() []float64 {
+	out := make([]float64, len(d.Y))
+	for i, y := range d.Y {
+		out[i] = float64(2*y - 1)
+	}
+	return out
+}
+
+// Tensors returns the points as a [n, 2] tensor and the labels as a [n] tensor
+func (d *Dataset) Tensors() (*migete.Tensor[float64], *migete.Tensor[int]) {
+	x := make([]float64, 0, 2*d.Len())
+	for _, p := range d.X {
+		x = append(x, p...)
+	}
+	y := append([]int(nil), d.Y...)
+	return migete.NewTensor(migete.MakeShape(d.Len(), 2), x, false),
+		migete.NewTensor(migete.MakeShape(d.Len()), y, false)
+}
+
+// Moons is two interleaving half circles
+func Moons(cfg Config) *Dataset {
+	g := newGenerator(cfg, 2)
+	outer := g.samples / 2
+	for i := 0; i < g.samples; i++ {
+		if i < outer {
+			t := math.Pi * float64(i) / float64(max(outer-1, 1))
+			g.add(math.Cos(t), math.Sin(t), 0)
+		} else {
+			inner := g.samples - outer
+			t := math.Pi * float64(i-outer) / float64(max(inner-1, 1))
+			g.add(1-math.Cos(t), 0.5-math.Sin(t), 1)
+		}
+	}
+	return g.dataset()
+}
+
+// Circles is one ring per class, with radii evenly spaced up to 1
+func Circles(cfg Config) *Dataset {
+	g := newGenerator(cfg, cfg.Classes)
+	for i := 0; i < g.samples; i++ {
+		class := g.class(i)
+		radius := float64(class+1) / float64(g.classes)
+		t := 2 * math.Pi * g.rng.Float64()
+		g.add(radius*math.Cos(t), radius*math.Sin(t), class)
+	}
+	return g.dataset()
+}
+
+// Blobs is one isotropic Gaussian blob per class, with the centers evenly
+// spaced on a circle of radius 3. The noise is the standard deviation of the blobs.
+func Blobs(cfg Config) *Dataset {
+	g := newGenerator(cfg, cfg.Classes)
+	for i := 0; i < g.samples; i++ {
+		class := g.class(i)
+		t := 2 * math.Pi * float64(class) / float64(g.classes)
+		g.add(3*math.Cos(t), 3*math.Sin(t), class)
+	}
+	return g.dataset()
+}
+
+// Spirals is one spiral arm per class winding out from the origin
+func Spirals(cfg Config) *Dataset {
+	g := newGenerator(cfg, cfg.Classes)
+	perClass := (g.samples + g.classes - 1) / g.classes
+	for i := 0; i < g.samples; i++ {
+		class := g.class(i)
+		k := i / g.classes
+		r := float64(k) / float64(max(perClass-1, 1))
+		t := 4*r + 2*math.Pi*float64(class)/float64(g.classes)
+		g.add(r*math.Sin(t), r*math.Cos(t), class)
+	}
+	return g.dataset()
+}
+
+// XOR is points drawn uniformly from [-1, 1]^2, labelled 1 when the signs of the
+// coordinates differ
+func XOR(cfg Config) *Dataset {
+	g := newGenerator(cfg, 2)
+	for i := 0; i < g.samples; i++ {
+		x := g.rng.Float64()*2 - 1
+		y := g.rng.Float64()*2 - 1
+		class := 0
+		if (x > 0) != (y > 0) {
+			class = 1
+		}
+		g.add(x, y, class)
+	}
+	return g.dataset()
+}
+
+type generator struct {
+	cfg     Config
+	samples int
+	classes int
+	rng     *rand.Rand
+	data    *Dataset
+}
+
+// newGenerator starts a dataset with the given number of classes, 0 meaning 2
+func newGenerator(cfg Config, classes int) *generator {
+	g := &generator{cfg: cfg, samples: cfg.Samples, classes: classes, rng: rand.New(rand.NewSource(cfg.Seed))}
+	if g.samples <= 0 {
+		g.samples = 100
+	}
+	if g.classes <= 0 {
+		g.classes = 2
+	}
+	g.data = &Dataset{X: make([][]float64, 0, g.samples), Y: make([]int, 0, g.samples), Classes: g.classes}
+	return g
+}
+
+// class spreads the samples evenly over the classes
+func (g *generator) class(i int) int {
+	return i % g.classes
+}
+
+func (g *generator) add(x, y float64, class int) {
+	x += g.rng.NormFloat64() * g.cfg.Noise
+	y += g.rng.NormFloat64() * g.cfg.Noise
+	g.data.X = append(g.data.X, []float64{x, y})
+	g.data.Y = append(g.data.Y, class)
+}
+
+// dataset shuffles the generated points so the classes are interleaved
+func (g *generator) dataset() *Dataset {
+	d := g.data
+	g.rng.Shuffle(d.Len(), func(i, j int) {
+		d.X[i], d.X[j] = d.X[j], d.X[i]
+		d.Y[i], d.Y[j] = d.Y[j], d.Y[i]
+	})
+	return d
+}
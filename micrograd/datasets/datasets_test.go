@@ -0,0 +1,87 @@
+package datasets
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestGenerators(t *testing.T) {
+	generators := map[string]func(Config) *Dataset{
+		"moons": Moons, "circles": Circles, "blobs": Blobs, "spirals": Spirals, "xor": XOR,
+	}
+	for name, gen := range generators {
+		d := gen(Config{Samples: 90, Noise: 0.1, Seed: 3})
+		if d.Len() != 90 || len(d.Y) != 90 {
+			t.Errorf("%s: got %d points and %d labels, want 90", name, d.Len(), len(d.Y))
+		}
+		counts := make([]int, d.Classes)
+		for _, y := range d.Y {
+			counts[y]++
+		}
+		for class, n := range counts {
+			if n < 30 {
+				t.Errorf("%s: class %d has only %d points", name, class, n)
+			}
+		}
+		if again := gen(Config{Samples: 90, Noise: 0.1, Seed: 3}); !reflect.DeepEqual(d, again) {
+			t.Errorf("%s: the same seed generated different datasets", name)
+		}
+		if other := gen(Config{Samples: 90, Noise: 0.1, Seed: 4}); reflect.DeepEqual(d.X, other.X) {
+			t.Errorf("%s: different seeds generated the same points", name)
+		}
+	}
+}
+
+func TestClassCount(t *testing.T) {
+	for name, gen := range map[string]func(Config) *Dataset{"circles": Circles, "blobs": Blobs, "spirals": Spirals} {
+		d := gen(Config{Samples: 60, Classes: 3})
+		if d.Classes != 3 {
+			t.Errorf("%s: got %d classes, want 3", name, d.Classes)
+		}
+		for _, y := range d.Y {
+			if y < 0 || y >= 3 {
+				t.Fatalf("%s: label %d out of range", name, y)
+			}
+		}
+	}
+	if d := Moons(Config{Classes: 5}); d.Classes != 2 {
+		t.Errorf("moons: got %d classes, want 2", d.Classes)
+	}
+}
+
+func TestCirclesWithoutNoise(t *testing.T) {
+	d := Circles(Config{Samples: 40, Classes: 4})
+	for i, p := range d.X {
+		radius := math.Hypot(p[0], p[1])
+		if want := float64(d.Y[i]+1) / 4; math.Abs(radius-want) > 1e-12 {
+			t.Errorf("point %v of class %d has radius %v, want %v", p, d.Y[i], radius, want)
+		}
+	}
+}
+
+func TestXORLabels(t *testing.T) {
+	d := XOR(Config{Samples: 50})
+	for i, p := range d.X {
+		if want := (p[0] > 0) != (p[1] > 0); (d.Y[i] == 1) != want {
+			t.Errorf("point %v labelled %d", p, d.Y[i])
+		}
+	}
+}
+
+func TestConversions(t *testing.T) {
+	d := &Dataset{X: [][]float64{{1, 2}, {3, 4}}, Y: []int{0, 1}, Classes: 2}
+	if got := d.Signed(); !reflect.DeepEqual(got, []float64{-1, 1}) {
+		t.Errorf("Signed() = %v", got)
+	}
+	if got := d.Targets(); !reflect.DeepEqual(got, []float64{0, 1}) {
+		t.Errorf("Targets() = %v", got)
+	}
+	x, y := d.Tensors()
+	if !reflect.DeepEqual([]int(x.Shape()), []int{2, 2}) || x.Get(1, 0) != 3 {
+		t.Errorf("X tensor = %v", x)
+	}
+	if !reflect.DeepEqual([]int(y.Shape()), []int{2}) || y.Get(1) != 1 {
+		t.Errorf("Y tensor = %v", y)
+	}
+}
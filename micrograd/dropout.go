@@ -0,0 +1,56 @@
+package micrograd
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Dropout zeroes every input with probability P while training and scales the
+// others by 1/(1-P), so that evaluation can use the inputs as they are
+// (inverted dropout). In evaluation mode it passes its inputs through.
+type Dropout struct {
+	P    float64
+	rng  *rand.Rand
+	eval bool
+}
+
+// NewDropout drops inputs with probability p, drawing from r or from the global
+// math/rand source when r is nil
+func NewDropout(p float64, r *rand.Rand) *Dropout {
+	if p < 0 || p >= 1 {
+		panic("dropout probability must be in [0, 1)")
+	}
+	if r == nil {
+		r = rand.New(globalSource{})
+	}
+	return &Dropout{P: p, rng: r}
+}
+
+func (d *Dropout) Forward(x []*Value) []*Value {
+	if d.eval || d.P == 0 {
+		return x
+	}
+	out := make([]*Value, len(x))
+	scale := 1 / (1 - d.P)
+	for i := range x {
+		if d.rng.Float64() < d.P {
+			out[i] = x[i].Mul(Constant(0))
+		} else {
+			out[i] = x[i].Mul(Constant(scale))
+		}
+	}
+	return out
+}
+
+// SetTraining switches between training (dropping inputs) and evaluation mode
+func (d *Dropout) SetTraining(training bool) {
+	d.eval = !training
+}
+
+func (d *Dropout) Training() bool {
+	return !d.eval
+}
+
+func (d *Dropout) String() string {
+	return fmt.Sprintf("Dropout(%v)", d.P)
+}
@@ -0,0 +1,36 @@
+package micrograd
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestDropout(t *testing.T) {
+	d := NewDropout(0.25, rand.New(rand.NewSource(1)))
+	x := make([]*Value, 10000)
+	for i := range x {
+		x[i] = NewValue(1.0)
+	}
+	out := d.Forward(x)
+	Sum(out...).Backward()
+	dropped := 0
+	for i, o := range out {
+		switch {
+		case o.Data == 0 && x[i].Grad == 0:
+			dropped++
+		case o.Data != 1/0.75 || x[i].Grad != 1/0.75:
+			t.Fatalf("output %d: got %v with grad %v, want 0 or %v", i, o.Data, x[i].Grad, 1/0.75)
+		}
+	}
+	if rate := float64(dropped) / float64(len(x)); math.Abs(rate-0.25) > 0.02 {
+		t.Errorf("dropped %v of the inputs, want 0.25", rate)
+	}
+
+	d.SetTraining(false)
+	for i, o := range d.Forward(x) {
+		if o != x[i] {
+			t.Fatal("evaluation mode changed the inputs")
+		}
+	}
+}
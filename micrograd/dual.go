@@ -0,0 +1,180 @@
+package micrograd
+
+import (
+	"fmt"
+	"math"
+)
+
+// Dual is a dual number a + b*eps with eps^2 = 0, used for forward mode automatic
+// differentiation: carrying the derivative of every input along a direction in
+// Tangent gives the directional derivative of the result in its Tangent.
+// Its methods mirror those of Value, but Duals are plain values that record no graph.
+type Dual struct {
+	Data    float64
+	Tangent float64
+}
+
+// NewDual makes a constant, use Dual{Data: x, Tangent: 1} for the variable to differentiate by
+func NewDual(data float64) Dual {
+	return Dual{Data: data}
+}
+
+func (l Dual) String() string {
+	return fmt.Sprintf("[%f, tangent: %f]", l.Data, l.Tangent)
+}
+
+func (l Dual) Add(r Dual) Dual {
+	return Dual{l.Data + r.Data, l.Tangent + r.Tangent}
+}
+
+func (l Dual) Mul(r Dual) Dual {
+	return Dual{l.Data * r.Data, l.Tangent*r.Data + l.Data*r.Tangent}
+}
+
+func (l Dual) Pow(r float64) Dual {
+	return Dual{math.Pow(l.Data, r), r * math.Pow(l.Data, r-1) * l.Tangent}
+}
+
+func (l Dual) Neg() Dual {
+	return Dual{-l.Data, -l.Tangent}
+}
+
+func (l Dual) Sub(r Dual) Dual {
+	return l.Add(r.Neg())
+}
+
+func (l Dual) Div(r Dual) Dual {
+	return l.Mul(r.Pow(-1.0))
+}
+
+func (l Dual) Relu() Dual {
+	if l.Data > 0 {
+		return l
+	}
+	return Dual{}
+}
+
+func (l Dual) LeakyRelu(alpha float64) Dual {
+	if l.Data > 0 {
+		return l
+	}
+	return Dual{alpha * l.Data, alpha * l.Tangent}
+}
+
+func (l Dual) Exp() Dual {
+	e := math.Exp(l.Data)
+	return Dual{e, e * l.Tangent}
+}
+
+func (l Dual) Log() Dual {
+	return Dual{math.Log(l.Data), l.Tangent / l.Data}
+}
+
+func (l Dual) Sqrt() Dual {
+	s := math.Sqrt(l.Data)
+	return Dual{s, l.Tangent / (2 * s)}
+}
+
+func (l Dual) Abs() Dual {
+	switch {
+	case l.Data > 0:
+		return l
+	case l.Data < 0:
+		return l.Neg()
+	}
+	return Dual{}
+}
+
+func (l Dual) Sin() Dual {
+	return Dual{math.Sin(l.Data), math.Cos(l.Data) * l.Tangent}
+}
+
+func (l Dual) Cos() Dual {
+	return Dual{math.Cos(l.Data), -math.Sin(l.Data) * l.Tangent}
+}
+
+func (l Dual) Tanh() Dual {
+	t := math.Tanh(l.Data)
+	return Dual{t, (1 - t*t) * l.Tangent}
+}
+
+func (l Dual) Sigmoid() Dual {
+	s := sigmoid(l.Data)
+	return Dual{s, s * (1 - s) * l.Tangent}
+}
+
+func (l Dual) Silu() Dual {
+	s := sigmoid(l.Data)
+	return Dual{l.Data * s, s * (1 + l.Data*(1-s)) * l.Tangent}
+}
+
+func (l Dual) NormalCDF() Dual {
+	return Dual{normalCDF(l.Data), normalPDF(l.Data) * l.Tangent}
+}
+
+func (l Dual) Gelu() Dual {
+	return Dual{l.Data * normalCDF(l.Data), (normalCDF(l.Data) + l.Data*normalPDF(l.Data)) * l.Tangent}
+}
+
+func (l Dual) Softplus() Dual {
+	return Dual{math.Max(l.Data, 0) + math.Log1p(math.Exp(-math.Abs(l.Data))), sigmoid(l.Data) * l.Tangent}
+}
+
+func (l Dual) LogSigmoid() Dual {
+	return Dual{math.Min(l.Data, 0) - math.Log1p(math.Exp(-math.Abs(l.Data))), sigmoid(-l.Data) * l.Tangent}
+}
+
+// StopGradient keeps the Data and drops the Tangent
+func (l Dual) StopGradient() Dual {
+	return Dual{Data: l.Data}
+}
+
+// DualSum is the forward mode counterpart of Sum
+func DualSum(values ...Dual) Dual {
+	out := Dual{}
+	for _, v := range values {
+		out = out.Add(v)
+	}
+	return out
+}
+
+// JVP evaluates f at x and returns its outputs together with the Jacobian-vector
+// product J(x) v, in a single forward pass
+func JVP(f func([]Dual) []Dual, x, v []float64) (y, jv []float64) {
+	if len(x) != len(v) {
+		panic("input and direction size mismatch")
+	}
+	inputs := make([]Dual, len(x))
+	for i := range x {
+		inputs[i] = Dual{x[i], v[i]}
+	}
+	outputs := f(inputs)
+	y = make([]float64, len(outputs))
+	jv = make([]float64, len(outputs))
+	for i, o := range outputs {
+		y[i], jv[i] = o.Data, o.Tangent
+	}
+	return y, jv
+}
+
+// Jacobian computes the full Jacobian of f at x with one forward pass per input,
+// jacobian[i][j] is d output_i / d input_j
+func Jacobian(f func([]Dual) []Dual, x []float64) [][]float64 {
+	var jacobian [][]float64
+	v := make([]float64, len(x))
+	for j := range x {
+		v[j] = 1
+		_, column := JVP(f, x, v)
+		v[j] = 0
+		if jacobian == nil {
+			jacobian = make([][]float64, len(column))
+			for i := range jacobian {
+				jacobian[i] = make([]float64, len(x))
+			}
+		}
+		for i := range column {
+			jacobian[i][j] = column[i]
+		}
+	}
+	return jacobian
+}
@@ -0,0 +1,67 @@
+package micrograd
+
+import (
+	"math"
+	"testing"
+)
+
+// dualEveryOp is everyOp written with dual numbers
+func dualEveryOp(v []Dual) Dual {
+	a, b, c := v[0], v[1], v[2]
+	terms := []Dual{
+		a.Add(b).Mul(c),
+		a.Pow(3).Add(b.Pow(-2)),
+		a.Sub(b).Relu().Add(b.Sub(a).LeakyRelu(0.1)),
+		c.Exp().Log().Sqrt().Abs(),
+		a.Sin().Mul(b.Cos()),
+		a.Tanh().Add(b.Sigmoid()).Add(c.Silu()).Add(a.Gelu()).Add(b.NormalCDF()),
+		b.Softplus().Add(c.LogSigmoid()),
+		a.Div(c).Neg(),
+	}
+	return DualSum(terms...).Mul(NewDual(1.0 / float64(len(terms))))
+}
+
+func TestJVPMatchesBackward(t *testing.T) {
+	f := func(v []Dual) []Dual { return []Dual{dualEveryOp(v)} }
+	for _, x := range [][]float64{{0.7, -1.3, 2.1}, {-0.4, 0.9, 0.3}, {1.5, 2.5, 0.6}} {
+		values := []*Value{NewValue(x[0]), NewValue(x[1]), NewValue(x[2])}
+		out := everyOp(values)
+		out.Backward()
+
+		dir := []float64{0.3, -1.0, 2.0}
+		y, jv := JVP(f, x, dir)
+		want := 0.0
+		for i := range values {
+			want += values[i].Grad * dir[i]
+		}
+		if math.Abs(y[0]-out.Data) > 1e-12 {
+			t.Errorf("x=%v: forward %v, engine %v", x, y[0], out.Data)
+		}
+		if math.Abs(jv[0]-want) > 1e-9 {
+			t.Errorf("x=%v: jvp %v, grad . v %v", x, jv[0], want)
+		}
+
+		jacobian := Jacobian(f, x)
+		for i := range values {
+			if math.Abs(jacobian[0][i]-values[i].Grad) > 1e-9 {
+				t.Errorf("x=%v: d/dx%d forward %v, backward %v", x, i, jacobian[0][i], values[i].Grad)
+			}
+		}
+	}
+}
+
+func TestJacobianVectorFunction(t *testing.T) {
+	// f(x, y) = (x*y, sin(x), y^2)
+	f := func(v []Dual) []Dual {
+		return []Dual{v[0].Mul(v[1]), v[0].Sin(), v[1].Pow(2)}
+	}
+	got := Jacobian(f, []float64{2, 3})
+	want := [][]float64{{3, 2}, {math.Cos(2), 0}, {0, 6}}
+	for i := range want {
+		for j := range want[i] {
+			if math.Abs(got[i][j]-want[i][j]) > 1e-12 {
+				t.Errorf("J[%d][%d] = %v, want %v", i, j, got[i][j], want[i][j])
+			}
+		}
+	}
+}
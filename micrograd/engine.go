@@ -9,17 +9,41 @@ import (
 )
 
 type Value struct {
-	Data     float64
-	Grad     float64
-	prev     []*Value
-	op       string
+	Data float64
+	Grad float64
+	prev []*Value
+	op   string
+	// arg is the parameter of parametrized ops, like the exponent of Pow
+	arg      float64
 	backward func()
+	// constant marks leaves made by Constant, which Simplify may fold
+	constant bool
+	// dataDependent marks constants picked from the Data of other values while
+	// the graph was built, which a Tape cannot recompute, see Compile
+	dataDependent bool
+	// frozen parameters are skipped by the optimizers, see Freeze
+	frozen bool
+	// nograd marks values of an inference pass, see NoGradValue
+	nograd bool
 }
 
 func NewValue(data float64) *Value {
 	return &Value{Data: data, Grad: 0, prev: []*Value{}, op: "", backward: func() {}}
 }
 
+// Constant makes a leaf for a literal like the -1 of Neg. It behaves like any
+// other leaf, but Simplify is free to fold it into the values that use it.
+func Constant(data float64) *Value {
+	v := NewValue(data)
+	v.constant = true
+	return v
+}
+
+// IsConstant tells if v was made by Constant
+func (v *Value) IsConstant() bool {
+	return v.constant
+}
+
 func makeValue(data float64, prev []*Value, op string) *Value {
 	v := NewValue(data)
 	v.prev = prev
@@ -33,7 +57,11 @@ func (v *Value) String() string {
 }
 
 func (l *Value) Add(r *Value) *Value {
-	out := makeValue(l.Data+r.Data, []*Value{l, r}, "+")
+	data := l.Data + r.Data
+	if l.nograd || r.nograd {
+		return NoGradValue(data)
+	}
+	out := makeValue(data, []*Value{l, r}, "+")
 	out.backward = func() {
 		l.Grad += out.Grad
 		r.Grad += out.Grad
@@ -43,7 +71,11 @@ func (l *Value) Add(r *Value) *Value {
 }
 
 func (l *Value) Mul(r *Value) *Value {
-	out := makeValue(l.Data*r.Data, []*Value{l, r}, "*")
+	data := l.Data * r.Data
+	if l.nograd || r.nograd {
+		return NoGradValue(data)
+	}
+	out := makeValue(data, []*Value{l, r}, "*")
 	out.backward = func() {
 		l.Grad += r.Data * out.Grad
 		r.Grad += l.Data * out.Grad
@@ -53,7 +85,12 @@ func (l *Value) Mul(r *Value) *Value {
 }
 
 func (l *Value) Pow(r float64) *Value {
-	out := makeValue(math.Pow(l.Data, r), []*Value{l}, fmt.Sprintf("^%v", r))
+	data := math.Pow(l.Data, r)
+	if l.nograd {
+		return NoGradValue(data)
+	}
+	out := makeValue(data, []*Value{l}, fmt.Sprintf("^%v", r))
+	out.arg = r
 	out.backward = func() {
 		l.Grad += r * math.Pow(l.Data, r-1) * out.Grad
 	}
@@ -62,18 +99,241 @@ func (l *Value) Pow(r float64) *Value {
 }
 
 func (l *Value) Relu() *Value {
-	out := makeValue(math.Max(0, l.Data), []*Value{l}, "ReLU")
+	data := math.Max(0, l.Data)
+	if l.nograd {
+		return NoGradValue(data)
+	}
+	out := makeValue(data, []*Value{l}, "ReLU")
+	out.backward = func() {
+		if l.Data > 0 {
+			l.Grad += out.Grad
+		}
+	}
+
+	return out
+}
+
+func (l *Value) LeakyRelu(alpha float64) *Value {
+	data := l.Data
+	if data < 0 {
+		data *= alpha
+	}
+	if l.nograd {
+		return NoGradValue(data)
+	}
+	out := makeValue(data, []*Value{l}, fmt.Sprintf("LeakyReLU(%v)", alpha))
+	out.arg = alpha
 	out.backward = func() {
 		if l.Data > 0 {
 			l.Grad += out.Grad
+		} else {
+			l.Grad += alpha * out.Grad
 		}
 	}
 
 	return out
 }
 
+func (l *Value) Exp() *Value {
+	data := math.Exp(l.Data)
+	if l.nograd {
+		return NoGradValue(data)
+	}
+	out := makeValue(data, []*Value{l}, "exp")
+	out.backward = func() {
+		l.Grad += out.Data * out.Grad
+	}
+
+	return out
+}
+
+func (l *Value) Log() *Value {
+	data := math.Log(l.Data)
+	if l.nograd {
+		return NoGradValue(data)
+	}
+	out := makeValue(data, []*Value{l}, "log")
+	out.backward = func() {
+		l.Grad += out.Grad / l.Data
+	}
+
+	return out
+}
+
+func (l *Value) Sqrt() *Value {
+	data := math.Sqrt(l.Data)
+	if l.nograd {
+		return NoGradValue(data)
+	}
+	out := makeValue(data, []*Value{l}, "sqrt")
+	out.backward = func() {
+		l.Grad += out.Grad / (2 * out.Data)
+	}
+
+	return out
+}
+
+func (l *Value) Abs() *Value {
+	data := math.Abs(l.Data)
+	if l.nograd {
+		return NoGradValue(data)
+	}
+	out := makeValue(data, []*Value{l}, "abs")
+	out.backward = func() {
+		// the subgradient at 0 is taken to be 0
+		if l.Data > 0 {
+			l.Grad += out.Grad
+		} else if l.Data < 0 {
+			l.Grad -= out.Grad
+		}
+	}
+
+	return out
+}
+
+func (l *Value) Sin() *Value {
+	data := math.Sin(l.Data)
+	if l.nograd {
+		return NoGradValue(data)
+	}
+	out := makeValue(data, []*Value{l}, "sin")
+	out.backward = func() {
+		l.Grad += math.Cos(l.Data) * out.Grad
+	}
+
+	return out
+}
+
+func (l *Value) Cos() *Value {
+	data := math.Cos(l.Data)
+	if l.nograd {
+		return NoGradValue(data)
+	}
+	out := makeValue(data, []*Value{l}, "cos")
+	out.backward = func() {
+		l.Grad -= math.Sin(l.Data) * out.Grad
+	}
+
+	return out
+}
+
+func (l *Value) Tanh() *Value {
+	data := math.Tanh(l.Data)
+	if l.nograd {
+		return NoGradValue(data)
+	}
+	out := makeValue(data, []*Value{l}, "tanh")
+	out.backward = func() {
+		l.Grad += (1 - out.Data*out.Data) * out.Grad
+	}
+
+	return out
+}
+
+func (l *Value) Sigmoid() *Value {
+	data := sigmoid(l.Data)
+	if l.nograd {
+		return NoGradValue(data)
+	}
+	out := makeValue(data, []*Value{l}, "sigmoid")
+	out.backward = func() {
+		l.Grad += out.Data * (1 - out.Data) * out.Grad
+	}
+
+	return out
+}
+
+// SiLU (a.k.a. swish) is x * sigmoid(x)
+func (l *Value) Silu() *Value {
+	data := l.Data * sigmoid(l.Data)
+	if l.nograd {
+		return NoGradValue(data)
+	}
+	out := makeValue(data, []*Value{l}, "SiLU")
+	out.backward = func() {
+		s := sigmoid(l.Data)
+		l.Grad += s * (1 + l.Data*(1-s)) * out.Grad
+	}
+
+	return out
+}
+
+// NormalCDF is the standard normal cumulative distribution function Phi(x)
+func (l *Value) NormalCDF() *Value {
+	data := normalCDF(l.Data)
+	if l.nograd {
+		return NoGradValue(data)
+	}
+	out := makeValue(data, []*Value{l}, "Phi")
+	out.backward = func() {
+		l.Grad += normalPDF(l.Data) * out.Grad
+	}
+
+	return out
+}
+
+// GELU uses the exact formulation x * Phi(x) where Phi is the standard normal CDF
+func (l *Value) Gelu() *Value {
+	data := l.Data * normalCDF(l.Data)
+	if l.nograd {
+		return NoGradValue(data)
+	}
+	out := makeValue(data, []*Value{l}, "GELU")
+	out.backward = func() {
+		l.Grad += (normalCDF(l.Data) + l.Data*normalPDF(l.Data)) * out.Grad
+	}
+
+	return out
+}
+
+// Softplus computes log(1 + exp(x)) without overflowing for large x.
+// The logistic loss of a score s with label y in {-1, 1} is s.Mul(-y).Softplus()
+func (l *Value) Softplus() *Value {
+	data := math.Max(l.Data, 0) + math.Log1p(math.Exp(-math.Abs(l.Data)))
+	if l.nograd {
+		return NoGradValue(data)
+	}
+	out := makeValue(data, []*Value{l}, "softplus")
+	out.backward = func() {
+		l.Grad += sigmoid(l.Data) * out.Grad
+	}
+
+	return out
+}
+
+// LogSigmoid computes log(sigmoid(x)) = -softplus(-x) in a numerically stable way
+func (l *Value) LogSigmoid() *Value {
+	data := math.Min(l.Data, 0) - math.Log1p(math.Exp(-math.Abs(l.Data)))
+	if l.nograd {
+		return NoGradValue(data)
+	}
+	out := makeValue(data, []*Value{l}, "logsigmoid")
+	out.backward = func() {
+		l.Grad += sigmoid(-l.Data) * out.Grad
+	}
+
+	return out
+}
+
+// StopGradient passes the Data of l forward but no gradient back: l gets no
+// contribution from the values computed from the result. Unlike Detach it stays
+// in the graph, so a Tape recomputes it. The straight-through estimator of a
+// rounding is x.Add(NewValue(math.Round(x.Data) - x.Data).StopGradient()),
+// or more generally x.Add(f(x).Sub(x).StopGradient()).
+func (l *Value) StopGradient() *Value {
+	if l.nograd {
+		return NoGradValue(l.Data)
+	}
+	return makeValue(l.Data, []*Value{l}, "stopgrad")
+}
+
+// Detach returns a new leaf with the Data of l, cut from the graph of l
+func (l *Value) Detach() *Value {
+	return NewValue(l.Data)
+}
+
 func (l *Value) Neg() *Value {
-	return l.Mul(NewValue(-1.0))
+	return l.Mul(Constant(-1.0))
 }
 
 func (l *Value) Sub(r *Value) *Value {
@@ -84,21 +344,38 @@ func (l *Value) Div(r *Value) *Value {
 	return l.Mul(r.Pow(-1.0))
 }
 
-func (l *Value) Backward() {
-	// topological order all of the children in the graph
-	topo := []*Value{}
-	visited := map[*Value]bool{}
-	var buildTopo func(*Value)
-	buildTopo = func(v *Value) {
-		if !visited[v] {
-			visited[v] = true
-			for _, child := range v.prev {
-				buildTopo(child)
-			}
-			topo = append(topo, v)
+// Sum adds up any number of values with a single node, which keeps the graph
+// shallow compared to a chain of Adds
+func Sum(values ...*Value) *Value {
+	data := 0.0
+	nograd := false
+	for _, v := range values {
+		data += v.Data
+		nograd = nograd || v.nograd
+	}
+	if nograd {
+		return NoGradValue(data)
+	}
+	// copy the inputs so the graph does not change if the caller reuses the slice
+	prev := append([]*Value(nil), values...)
+	out := makeValue(data, prev, "sum")
+	out.backward = func() {
+		for _, v := range prev {
+			v.Grad += out.Grad
 		}
 	}
-	buildTopo(l)
+
+	return out
+}
+
+// Mean is the average of the values
+func Mean(values ...*Value) *Value {
+	return Sum(values...).Mul(Constant(1.0 / float64(len(values))))
+}
+
+func (l *Value) Backward() {
+	// topological order all of the children in the graph
+	topo := l.topo()
 
 	l.Grad = 1.0
 	// go in the reverse order of topo and call each backward
@@ -107,6 +384,36 @@ func (l *Value) Backward() {
 	}
 }
 
+// topo returns the graph rooted at l in topological order (children before parents).
+// It walks the graph with an explicit stack instead of recursion so that very deep
+// graphs, like the long chains of Adds built by running sums, do not grow the
+// goroutine stack. The visited set is local to the walk and the values are only
+// read, so several goroutines can walk a shared graph at the same time.
+func (l *Value) topo() []*Value {
+	type frame struct {
+		v    *Value
+		next int // index of the next child of v to visit
+	}
+	topo := []*Value{}
+	visited := map[*Value]struct{}{l: {}}
+	stack := []frame{{v: l}}
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		if top.next < len(top.v.prev) {
+			child := top.v.prev[top.next]
+			top.next++
+			if _, ok := visited[child]; !ok {
+				visited[child] = struct{}{}
+				stack = append(stack, frame{v: child})
+			}
+			continue
+		}
+		topo = append(topo, top.v)
+		stack = stack[:len(stack)-1]
+	}
+	return topo
+}
+
 func (l *Value) PrintGraph() {
 	fmt.Println(l)
 	seen := map[*Value]bool{}
@@ -124,3 +431,20 @@ func (l *Value) PrintGraph() {
 	}
 	print(l, 0)
 }
+
+func sigmoid(x float64) float64 {
+	// avoid overflowing exp for large negative inputs
+	if x >= 0 {
+		return 1 / (1 + math.Exp(-x))
+	}
+	e := math.Exp(x)
+	return e / (1 + e)
+}
+
+func normalCDF(x float64) float64 {
+	return 0.5 * (1 + math.Erf(x/math.Sqrt2))
+}
+
+func normalPDF(x float64) float64 {
+	return math.Exp(-0.5*x*x) / math.Sqrt(2*math.Pi)
+}
@@ -0,0 +1,121 @@
+package micrograd
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+)
+
+func TestUnaryOps(t *testing.T) {
+	tests := []struct {
+		name     string
+		op       func(*Value) *Value
+		x        float64
+		wantData float64
+		wantGrad float64
+	}{
+		{"exp", (*Value).Exp, 1.0, math.E, math.E},
+		{"log", (*Value).Log, 2.0, math.Ln2, 0.5},
+		{"sqrt", (*Value).Sqrt, 4.0, 2.0, 0.25},
+		{"abs", (*Value).Abs, -3.0, 3.0, -1.0},
+		{"sin", (*Value).Sin, 0.5, math.Sin(0.5), math.Cos(0.5)},
+		{"cos", (*Value).Cos, 0.5, math.Cos(0.5), -math.Sin(0.5)},
+		{"tanh", (*Value).Tanh, 0.5, math.Tanh(0.5), 1 - math.Tanh(0.5)*math.Tanh(0.5)},
+		{"sigmoid", (*Value).Sigmoid, 0.0, 0.5, 0.25},
+		{"silu", (*Value).Silu, 0.0, 0.0, 0.5},
+		{"gelu", (*Value).Gelu, 0.0, 0.0, 0.5},
+		{"softplus", (*Value).Softplus, 0.0, math.Ln2, 0.5},
+		{"logsigmoid", (*Value).LogSigmoid, 0.0, -math.Ln2, 0.5},
+		{"leaky relu", func(v *Value) *Value { return v.LeakyRelu(0.1) }, -2.0, -0.2, 0.1},
+	}
+	for _, tt := range tests {
+		x := NewValue(tt.x)
+		y := tt.op(x)
+		y.Backward()
+		if math.Abs(y.Data-tt.wantData) > 1e-12 {
+			t.Errorf("%s(%v) = %v, want %v", tt.name, tt.x, y.Data, tt.wantData)
+		}
+		if math.Abs(x.Grad-tt.wantGrad) > 1e-12 {
+			t.Errorf("%s'(%v) = %v, want %v", tt.name, tt.x, x.Grad, tt.wantGrad)
+		}
+	}
+}
+
+func TestSoftplusLargeInputs(t *testing.T) {
+	if got := NewValue(1000).Softplus().Data; got != 1000 {
+		t.Errorf("softplus(1000) = %v, want 1000", got)
+	}
+	if got := NewValue(-1000).Softplus().Data; got != 0 {
+		t.Errorf("softplus(-1000) = %v, want 0", got)
+	}
+	if got := NewValue(-1000).Sigmoid().Data; math.IsNaN(got) || got != 0 {
+		t.Errorf("sigmoid(-1000) = %v, want 0", got)
+	}
+}
+
+func TestBackwardDeepChain(t *testing.T) {
+	const depth = 1_000_000
+	x := NewValue(1.0)
+	sum := NewValue(0.0)
+	for i := 0; i < depth; i++ {
+		sum = sum.Add(x)
+	}
+	sum.Backward()
+	if sum.Data != depth {
+		t.Errorf("sum = %v, want %v", sum.Data, depth)
+	}
+	if x.Grad != depth {
+		t.Errorf("x.Grad = %v, want %v", x.Grad, depth)
+	}
+}
+
+func TestBackwardSharedNodes(t *testing.T) {
+	// d = (a*b) + (a*b) reuses a and b through two paths
+	a := NewValue(2.0)
+	b := NewValue(-3.0)
+	c := a.Mul(b)
+	d := c.Add(c)
+	d.Backward()
+	if a.Grad != -6.0 || b.Grad != 4.0 {
+		t.Errorf("got a.Grad=%v b.Grad=%v, want -6 and 4", a.Grad, b.Grad)
+	}
+}
+
+func TestTopoConcurrent(t *testing.T) {
+	mlp := NewMLP(2, []int{8, 8, 1}, WithSeed(1))
+	loss := mlp.Forward([]*Value{NewValue(0.5), NewValue(-0.5)})[0].Pow(2)
+	want := len(loss.topo())
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				if n := len(loss.topo()); n != want {
+					t.Errorf("concurrent walk found %d values, want %d", n, want)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkBackwardChain(b *testing.B) {
+	for _, depth := range []int{1_000, 10_000, 100_000, 1_000_000} {
+		b.Run(fmt.Sprintf("depth=%d", depth), func(b *testing.B) {
+			x := NewValue(1.0)
+			sum := NewValue(0.0)
+			for i := 0; i < depth; i++ {
+				sum = sum.Add(x.Mul(x))
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				sum.Backward()
+			}
+			b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N)/float64(3*depth), "ns/node")
+		})
+	}
+}
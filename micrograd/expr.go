@@ -0,0 +1,609 @@
+package micrograd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Expr is a parsed expression like `tanh(a*b + c) ** 2`. Evaluating it with
+// bindings from variable names to Values builds the corresponding graph.
+//
+// The grammar, from the lowest to the highest precedence:
+//
+//	sum     = product { ("+" | "-") product }
+//	product = unary { ("*" | "/") unary }
+//	unary   = "-" unary | power
+//	power   = atom [ ("**" | "^") unary ]
+//	atom    = number | name | name "(" sum { "," sum } ")" | "(" sum ")"
+//
+// so -a**2 is -(a**2) and a**b**c is a**(b**c). Exponents must be constant,
+// as are the second argument of leaky_relu.
+type Expr struct {
+	src  string
+	root exprNode
+}
+
+// exprFunc is a function that can be called in an expression. The arguments
+// after the first args ones must be constant, args < 0 means any number of arguments.
+type exprFunc struct {
+	args   int
+	consts int
+	apply  func(args []*Value, consts []float64) *Value
+}
+
+func unaryFunc(op func(*Value) *Value) exprFunc {
+	return exprFunc{args: 1, apply: func(args []*Value, _ []float64) *Value { return op(args[0]) }}
+}
+
+var exprFuncs = map[string]exprFunc{
+	"relu":          unaryFunc((*Value).Relu),
+	"exp":           unaryFunc((*Value).Exp),
+	"log":           unaryFunc((*Value).Log),
+	"sqrt":          unaryFunc((*Value).Sqrt),
+	"abs":           unaryFunc((*Value).Abs),
+	"sin":           unaryFunc((*Value).Sin),
+	"cos":           unaryFunc((*Value).Cos),
+	"tanh":          unaryFunc((*Value).Tanh),
+	"sigmoid":       unaryFunc((*Value).Sigmoid),
+	"silu":          unaryFunc((*Value).Silu),
+	"gelu":          unaryFunc((*Value).Gelu),
+	"normal_cdf":    unaryFunc((*Value).NormalCDF),
+	"softplus":      unaryFunc((*Value).Softplus),
+	"logsigmoid":    unaryFunc((*Value).LogSigmoid),
+	"stop_gradient": unaryFunc((*Value).StopGradient),
+	"leaky_relu": {args: 1, consts: 1, apply: func(args []*Value, consts []float64) *Value {
+		return args[0].LeakyRelu(consts[0])
+	}},
+	"sum":  {args: -1, apply: func(args []*Value, _ []float64) *Value { return Sum(args...) }},
+	"mean": {args: -1, apply: func(args []*Value, _ []float64) *Value { return Mean(args...) }},
+}
+
+// ParseExpr parses src into an expression that can be evaluated many times
+func ParseExpr(src string) (*Expr, error) {
+	p := &exprParser{src: src}
+	p.next()
+	root, err := p.sum()
+	if err == nil && p.tok.kind != tokEOF {
+		err = p.errorf("unexpected %q", p.tok.text)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Expr{src: src, root: root}, nil
+}
+
+// Parse parses src and evaluates it with the given bindings in one step
+func Parse(src string, vars map[string]*Value) (*Value, error) {
+	e, err := ParseExpr(src)
+	if err != nil {
+		return nil, err
+	}
+	return e.Eval(vars)
+}
+
+func (e *Expr) String() string {
+	return e.src
+}
+
+// Variables lists the names used in the expression, sorted
+func (e *Expr) Variables() []string {
+	seen := map[string]bool{}
+	var walk func(n exprNode)
+	walk = func(n exprNode) {
+		switch n := n.(type) {
+		case nameNode:
+			seen[string(n)] = true
+		case negNode:
+			walk(n.x)
+		case binaryNode:
+			walk(n.l)
+			walk(n.r)
+		case powNode:
+			walk(n.x)
+		case callNode:
+			for _, a := range n.args {
+				walk(a)
+			}
+		}
+	}
+	walk(e.root)
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Eval builds the graph of the expression, using vars[name] for every variable.
+// Numbers become new leaves made by Constant. Every variable must be bound.
+func (e *Expr) Eval(vars map[string]*Value) (*Value, error) {
+	return e.root.eval(vars)
+}
+
+type exprNode interface {
+	eval(vars map[string]*Value) (*Value, error)
+}
+
+type (
+	numberNode float64
+	nameNode   string
+	negNode    struct{ x exprNode }
+	binaryNode struct {
+		op   byte
+		l, r exprNode
+	}
+	powNode struct {
+		x   exprNode
+		exp float64
+	}
+	callNode struct {
+		fn     exprFunc
+		args   []exprNode
+		consts []float64
+	}
+)
+
+func (n numberNode) eval(map[string]*Value) (*Value, error) {
+	return Constant(float64(n)), nil
+}
+
+func (n nameNode) eval(vars map[string]*Value) (*Value, error) {
+	if v, ok := vars[string(n)]; ok {
+		return v, nil
+	}
+	return nil, fmt.Errorf("unbound variable %q", string(n))
+}
+
+func (n negNode) eval(vars map[string]*Value) (*Value, error) {
+	x, err := n.x.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	return x.Neg(), nil
+}
+
+func (n binaryNode) eval(vars map[string]*Value) (*Value, error) {
+	l, err := n.l.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	r, err := n.r.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case '+':
+		return l.Add(r), nil
+	case '-':
+		return l.Sub(r), nil
+	case '*':
+		return l.Mul(r), nil
+	default:
+		return l.Div(r), nil
+	}
+}
+
+func (n powNode) eval(vars map[string]*Value) (*Value, error) {
+	x, err := n.x.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	return x.Pow(n.exp), nil
+}
+
+func (n callNode) eval(vars map[string]*Value) (*Value, error) {
+	args := make([]*Value, len(n.args))
+	for i, a := range n.args {
+		v, err := a.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	return n.fn.apply(args, n.consts), nil
+}
+
+// constant evaluates n if it does not use any variable
+func constant(n exprNode) (float64, bool) {
+	v, err := n.eval(nil)
+	if err != nil {
+		return 0, false
+	}
+	return v.Data, true
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokName
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+type exprParser struct {
+	src string
+	pos int
+	tok token
+}
+
+func (p *exprParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("parse error at offset %d in %q: %s", p.tok.pos, p.src, fmt.Sprintf(format, args...))
+}
+
+// next scans the following token into p.tok
+func (p *exprParser) next() {
+	for p.pos < len(p.src) {
+		r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		p.pos += size
+	}
+	start := p.pos
+	if p.pos == len(p.src) {
+		p.tok = token{kind: tokEOF, text: "end of input", pos: start}
+		return
+	}
+	c := p.src[p.pos]
+	r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+	switch {
+	case isDigit(c) || c == '.':
+		for p.pos < len(p.src) && (isDigit(p.src[p.pos]) || p.src[p.pos] == '.') {
+			p.pos++
+		}
+		// exponent, as in 1e-05
+		if p.pos < len(p.src) && (p.src[p.pos] == 'e' || p.src[p.pos] == 'E') {
+			end := p.pos + 1
+			if end < len(p.src) && (p.src[end] == '+' || p.src[end] == '-') {
+				end++
+			}
+			if end < len(p.src) && isDigit(p.src[end]) {
+				for end < len(p.src) && isDigit(p.src[end]) {
+					end++
+				}
+				p.pos = end
+			}
+		}
+		p.tok = token{kind: tokNumber, text: p.src[start:p.pos], pos: start}
+	case isNameStart(r):
+		for p.pos < len(p.src) {
+			r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+			if !isNameStart(r) && !unicode.IsDigit(r) {
+				break
+			}
+			p.pos += size
+		}
+		p.tok = token{kind: tokName, text: p.src[start:p.pos], pos: start}
+	case strings.HasPrefix(p.src[p.pos:], "**"):
+		p.pos += 2
+		p.tok = token{kind: tokOp, text: "**", pos: start}
+	default:
+		p.pos += size
+		p.tok = token{kind: tokOp, text: string(r), pos: start}
+	}
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isNameStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func (p *exprParser) isOp(ops ...string) bool {
+	if p.tok.kind != tokOp {
+		return false
+	}
+	for _, op := range ops {
+		if p.tok.text == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *exprParser) expect(op string) error {
+	if !p.isOp(op) {
+		return p.errorf("expected %q, got %q", op, p.tok.text)
+	}
+	p.next()
+	return nil
+}
+
+func (p *exprParser) sum() (exprNode, error) {
+	l, err := p.product()
+	for err == nil && p.isOp("+", "-") {
+		op := p.tok.text[0]
+		p.next()
+		var r exprNode
+		if r, err = p.product(); err == nil {
+			l = binaryNode{op: op, l: l, r: r}
+		}
+	}
+	return l, err
+}
+
+func (p *exprParser) product() (exprNode, error) {
+	l, err := p.unary()
+	for err == nil && p.isOp("*", "/") {
+		op := p.tok.text[0]
+		p.next()
+		var r exprNode
+		if r, err = p.unary(); err == nil {
+			l = binaryNode{op: op, l: l, r: r}
+		}
+	}
+	return l, err
+}
+
+func (p *exprParser) unary() (exprNode, error) {
+	if !p.isOp("-") {
+		return p.power()
+	}
+	p.next()
+	literal := p.tok.kind == tokNumber
+	x, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	// fold negative numbers so that -2 is a single constant
+	if n, ok := x.(numberNode); ok && literal {
+		return -n, nil
+	}
+	return negNode{x}, nil
+}
+
+func (p *exprParser) power() (exprNode, error) {
+	x, err := p.atom()
+	if err != nil || !p.isOp("**", "^") {
+		return x, err
+	}
+	p.next()
+	pos := p.tok.pos
+	e, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	exp, ok := constant(e)
+	if !ok {
+		p.tok.pos = pos
+		return nil, p.errorf("exponent must be a constant")
+	}
+	return powNode{x: x, exp: exp}, nil
+}
+
+func (p *exprParser) atom() (exprNode, error) {
+	tok := p.tok
+	switch {
+	case tok.kind == tokNumber:
+		f, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, p.errorf("invalid number %q", tok.text)
+		}
+		p.next()
+		return numberNode(f), nil
+	case tok.kind == tokName:
+		p.next()
+		if !p.isOp("(") {
+			return nameNode(tok.text), nil
+		}
+		fn, ok := exprFuncs[tok.text]
+		if !ok {
+			p.tok = tok
+			return nil, p.errorf("unknown function %q", tok.text)
+		}
+		return p.call(tok, fn)
+	case p.isOp("("):
+		p.next()
+		x, err := p.sum()
+		if err != nil {
+			return nil, err
+		}
+		return x, p.expect(")")
+	}
+	return nil, p.errorf("unexpected %q", tok.text)
+}
+
+func (p *exprParser) call(name token, fn exprFunc) (exprNode, error) {
+	p.next() // (
+	var args []exprNode
+	for !p.isOp(")") {
+		if len(args) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		a, err := p.sum()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, a)
+	}
+	p.next()
+
+	n := callNode{fn: fn, args: args}
+	switch {
+	case fn.args < 0 && len(args) == 0:
+		p.tok = name
+		return nil, p.errorf("%s needs at least one argument", name.text)
+	case fn.args >= 0 && len(args) != fn.args+fn.consts:
+		p.tok = name
+		return nil, p.errorf("%s takes %d arguments, got %d", name.text, fn.args+fn.consts, len(args))
+	case fn.consts > 0:
+		n.args = args[:fn.args]
+		for _, a := range args[fn.args:] {
+			c, ok := constant(a)
+			if !ok {
+				p.tok = name
+				return nil, p.errorf("%s: argument %d must be a constant", name.text, len(n.consts)+fn.args+1)
+			}
+			n.consts = append(n.consts, c)
+		}
+	}
+	return n, nil
+}
+
+// precedence levels of the infix printer, matching the grammar of Expr
+const (
+	precSum = iota + 1
+	precProduct
+	precUnary
+	precPower
+	precAtom
+)
+
+// functions printed by Infix, by opcode
+var infixFuncs = map[opcode]string{
+	opRelu:         "relu",
+	opExp:          "exp",
+	opLog:          "log",
+	opSqrt:         "sqrt",
+	opAbs:          "abs",
+	opSin:          "sin",
+	opCos:          "cos",
+	opTanh:         "tanh",
+	opSigmoid:      "sigmoid",
+	opSilu:         "silu",
+	opGelu:         "gelu",
+	opNormalCDF:    "normal_cdf",
+	opSoftplus:     "softplus",
+	opLogSigmoid:   "logsigmoid",
+	opStopGradient: "stop_gradient",
+}
+
+// Infix prints the graph rooted at v as an infix expression that Parse reads
+// back into the same graph. Leaves bound in names print as their name, other
+// leaves as their Data. Subtractions, negations and divisions built by Sub, Neg
+// and Div are recognized. Values used several times are printed every time.
+func Infix(v *Value, names map[string]*Value) string {
+	leaves := make(map[*Value]string, len(names))
+	for name, leaf := range names {
+		leaves[leaf] = name
+	}
+	var sb strings.Builder
+	writeInfix(&sb, v, leaves)
+	return sb.String()
+}
+
+// isConst tells if v is an unnamed leaf with the given data
+func isConst(v *Value, data float64, leaves map[*Value]string) bool {
+	_, named := leaves[v]
+	return len(v.prev) == 0 && !named && v.Data == data
+}
+
+// infixPrec is the precedence of the outermost operator Infix prints for v
+func infixPrec(v *Value, leaves map[*Value]string) int {
+	if len(v.prev) == 0 {
+		if _, named := leaves[v]; !named && (v.Data < 0 || v.Data == 0 && 1/v.Data < 0) {
+			return precUnary
+		}
+		return precAtom
+	}
+	code, err := opcodeOf(v)
+	if err != nil {
+		return precAtom
+	}
+	switch code {
+	case opAdd:
+		return precSum
+	case opMul:
+		if isConst(v.prev[1], -1, leaves) {
+			return precUnary
+		}
+		return precProduct
+	case opPow:
+		return precPower
+	}
+	return precAtom
+}
+
+// writeOperand writes v, in parentheses if its precedence is lower than min
+func writeOperand(sb *strings.Builder, v *Value, min int, leaves map[*Value]string) {
+	if infixPrec(v, leaves) < min {
+		sb.WriteByte('(')
+		writeInfix(sb, v, leaves)
+		sb.WriteByte(')')
+		return
+	}
+	writeInfix(sb, v, leaves)
+}
+
+func writeInfix(sb *strings.Builder, v *Value, leaves map[*Value]string) {
+	if len(v.prev) == 0 {
+		if name, ok := leaves[v]; ok {
+			sb.WriteString(name)
+		} else {
+			sb.WriteString(strconv.FormatFloat(v.Data, 'g', -1, 64))
+		}
+		return
+	}
+	code, err := opcodeOf(v)
+	if err != nil {
+		fmt.Fprintf(sb, "%s(...)", v.op)
+		return
+	}
+	switch code {
+	case opAdd:
+		l, r := v.prev[0], v.prev[1]
+		writeOperand(sb, l, precSum, leaves)
+		// l - r is built as l + (r * -1)
+		if len(r.prev) == 2 && r.op == "*" && isConst(r.prev[1], -1, leaves) {
+			sb.WriteString(" - ")
+			writeOperand(sb, r.prev[0], precProduct, leaves)
+			return
+		}
+		sb.WriteString(" + ")
+		writeOperand(sb, r, precProduct, leaves)
+	case opMul:
+		l, r := v.prev[0], v.prev[1]
+		if isConst(r, -1, leaves) {
+			sb.WriteByte('-')
+			writeOperand(sb, l, precPower, leaves)
+			return
+		}
+		writeOperand(sb, l, precProduct, leaves)
+		// l / r is built as l * r**-1
+		if r.op == "^-1" {
+			sb.WriteString(" / ")
+			writeOperand(sb, r.prev[0], precUnary, leaves)
+			return
+		}
+		sb.WriteString(" * ")
+		writeOperand(sb, r, precUnary, leaves)
+	case opPow:
+		writeOperand(sb, v.prev[0], precAtom, leaves)
+		sb.WriteString(" ** ")
+		sb.WriteString(strconv.FormatFloat(v.arg, 'g', -1, 64))
+	case opLeakyRelu:
+		sb.WriteString("leaky_relu(")
+		writeInfix(sb, v.prev[0], leaves)
+		fmt.Fprintf(sb, ", %s)", strconv.FormatFloat(v.arg, 'g', -1, 64))
+	case opSum:
+		sb.WriteString("sum(")
+		for i, child := range v.prev {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			writeInfix(sb, child, leaves)
+		}
+		sb.WriteByte(')')
+	default:
+		sb.WriteString(infixFuncs[code])
+		sb.WriteByte('(')
+		writeInfix(sb, v.prev[0], leaves)
+		sb.WriteByte(')')
+	}
+}
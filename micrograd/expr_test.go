@@ -0,0 +1,143 @@
+package micrograd
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	a, b, c := NewValue(0.5), NewValue(-1.5), NewValue(2.0)
+	vars := map[string]*Value{"a": a, "b": b, "c": c}
+	tests := []struct {
+		src  string
+		want float64
+	}{
+		{"tanh(a*b + c) ** 2", math.Pow(math.Tanh(0.5*-1.5+2), 2)},
+		{"a + b * c", 0.5 + -1.5*2},
+		{"(a + b) * c", (0.5 - 1.5) * 2},
+		{"a - b - c", 0.5 + 1.5 - 2},
+		{"a / b / c", 0.5 / -1.5 / 2},
+		{"-a ** 2", -0.25},
+		{"c ** 3 ** 0.5", math.Pow(2, math.Pow(3, 0.5))},
+		{"c ^ -1", 0.5},
+		{"leaky_relu(b, 0.1) + relu(b)", -0.15},
+		{"sum(a, b, c) + mean(a, c)", 1 + 1.25},
+		{"exp(log(c)) * sqrt(abs(b * 6)) + 1e-3", 6 + 1e-3},
+		{"normal_cdf(0) + sigmoid(0) + softplus(0) - logsigmoid(0)", 1 + 2*math.Ln2},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.src, vars)
+		if err != nil {
+			t.Errorf("%s: %v", tt.src, err)
+			continue
+		}
+		if math.Abs(got.Data-tt.want) > 1e-12 {
+			t.Errorf("%s = %v, want %v", tt.src, got.Data, tt.want)
+		}
+	}
+}
+
+func TestParseGradients(t *testing.T) {
+	vars := map[string]*Value{"a": NewValue(0.7), "b": NewValue(-1.3), "c": NewValue(2.1)}
+	x := []*Value{vars["a"], vars["b"], vars["c"]}
+	out, err := Parse("tanh(a*b + c) ** 2 - a / c", vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out.Backward()
+
+	y := []*Value{NewValue(0.7), NewValue(-1.3), NewValue(2.1)}
+	want := y[0].Mul(y[1]).Add(y[2]).Tanh().Pow(2).Sub(y[0].Div(y[2]))
+	want.Backward()
+	for i := range x {
+		if x[i].Grad != y[i].Grad {
+			t.Errorf("input %d: grad %v, want %v", i, x[i].Grad, y[i].Grad)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	vars := map[string]*Value{"a": NewValue(1)}
+	for _, tt := range []struct{ src, want string }{
+		{"a +", "unexpected"},
+		{"(a", `expected ")"`},
+		{"a ** a", "exponent must be a constant"},
+		{"foo(a)", "offset 0 in \"foo(a)\": unknown function"},
+		{"a + tanh(a, a)", "offset 4 in \"a + tanh(a, a)\": tanh takes 1 arguments"},
+		{"leaky_relu(a, a)", "offset 0 in \"leaky_relu(a, a)\": leaky_relu: argument 2 must be a constant"},
+		{"a*sum()", "offset 2 in \"a*sum()\": sum needs at least one argument"},
+		{"a b", "unexpected"},
+		{"a ÷ a", `offset 2 in "a ÷ a": unexpected "÷"`},
+		{"a + z", `unbound variable "z"`},
+	} {
+		_, err := Parse(tt.src, vars)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: got error %v, want %q", tt.src, err, tt.want)
+		}
+	}
+}
+
+func TestParseUnicodeNames(t *testing.T) {
+	vars := map[string]*Value{"α": NewValue(2), "β_1": NewValue(3)}
+	e, err := ParseExpr("α * β_1 + α")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(e.Variables(), ","); got != "α,β_1" {
+		t.Errorf("variables = %s, want α,β_1", got)
+	}
+	v, err := e.Eval(vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Data != 8 {
+		t.Errorf("got %v, want 8", v.Data)
+	}
+}
+
+func TestExprVariables(t *testing.T) {
+	e, err := ParseExpr("tanh(x*w1 + b) + w1 ** 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Join(e.Variables(), ",")
+	if got != "b,w1,x" {
+		t.Errorf("variables = %s, want b,w1,x", got)
+	}
+}
+
+func TestInfixRoundTrip(t *testing.T) {
+	vars := map[string]*Value{"a": NewValue(0.7), "b": NewValue(-1.3), "c": NewValue(2.1)}
+	for _, src := range []string{
+		"tanh(a * b + c) ** 2",
+		"a - (b - c)",
+		"a - b - c",
+		"a / (b * c)",
+		"-a ** 2",
+		"(-a) ** 2",
+		"-(-a)",
+		"a * -2 + 0.5",
+		"(a ** 2) ** 3",
+		"leaky_relu(a - b, 0.01) + sum(a, b, c) / gelu(c)",
+		"relu(a) * silu(b) * sin(c) * cos(a) * normal_cdf(b)",
+		"stop_gradient(a * b) + c",
+	} {
+		v, err := Parse(src, vars)
+		if err != nil {
+			t.Fatal(err)
+		}
+		printed := Infix(v, vars)
+		if printed != src {
+			t.Errorf("Infix(%s) = %s", src, printed)
+		}
+		again, err := Parse(printed, vars)
+		if err != nil {
+			t.Errorf("%s: %v", printed, err)
+			continue
+		}
+		if again.Data != v.Data || Infix(again, vars) != printed {
+			t.Errorf("%s does not round trip", src)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package micrograd
+
+import "math"
+
+// GradCheckResult compares the gradient computed by Backward for one input with
+// a numerical estimate of it
+type GradCheckResult struct {
+	Analytic float64
+	Numeric  float64
+	RelError float64
+}
+
+// GradCheck evaluates f at x, runs Backward and compares the gradient of every
+// input with a central finite difference (f(x+eps) - f(x-eps)) / 2eps.
+// f must build a fresh graph from its inputs on every call.
+func GradCheck(f func([]*Value) *Value, x []float64, eps float64) []GradCheckResult {
+	inputs := make([]*Value, len(x))
+	for i := range x {
+		inputs[i] = NewValue(x[i])
+	}
+	f(inputs).Backward()
+
+	results := make([]GradCheckResult, len(x))
+	for i := range x {
+		plus := evalAt(f, x, i, x[i]+eps)
+		minus := evalAt(f, x, i, x[i]-eps)
+		numeric := (plus - minus) / (2 * eps)
+		results[i] = GradCheckResult{
+			Analytic: inputs[i].Grad,
+			Numeric:  numeric,
+			RelError: relativeError(inputs[i].Grad, numeric),
+		}
+	}
+	return results
+}
+
+// MaxRelError returns the largest relative error found by GradCheck
+func MaxRelError(results []GradCheckResult) float64 {
+	worst := 0.0
+	for _, r := range results {
+		worst = math.Max(worst, r.RelError)
+	}
+	return worst
+}
+
+// evalAt evaluates f at x with the i-th input replaced by xi
+func evalAt(f func([]*Value) *Value, x []float64, i int, xi float64) float64 {
+	inputs := make([]*Value, len(x))
+	for j := range x {
+		inputs[j] = NewValue(x[j])
+	}
+	inputs[i].Data = xi
+	return f(inputs).Data
+}
+
+func relativeError(a, b float64) float64 {
+	scale := math.Max(math.Abs(a), math.Abs(b))
+	if scale == 0 {
+		return 0
+	}
+	return math.Abs(a-b) / scale
+}
@@ -0,0 +1,64 @@
+package micrograd
+
+import "testing"
+
+func TestGradCheckOps(t *testing.T) {
+	tests := []struct {
+		name string
+		f    func([]*Value) *Value
+		x    []float64
+	}{
+		{"add", func(v []*Value) *Value { return v[0].Add(v[1]) }, []float64{1.5, -2.0}},
+		{"mul", func(v []*Value) *Value { return v[0].Mul(v[1]) }, []float64{1.5, -2.0}},
+		{"pow", func(v []*Value) *Value { return v[0].Pow(3) }, []float64{1.3}},
+		{"pow fractional", func(v []*Value) *Value { return v[0].Pow(0.5) }, []float64{2.0}},
+		{"pow negative", func(v []*Value) *Value { return v[0].Pow(-1) }, []float64{-0.7}},
+		{"pow negative square", func(v []*Value) *Value { return v[0].Pow(-2) }, []float64{1.7}},
+		{"relu", func(v []*Value) *Value { return v[0].Relu().Add(v[1].Relu()) }, []float64{0.8, -0.4}},
+		{"leaky relu", func(v []*Value) *Value { return v[0].LeakyRelu(0.01).Add(v[1].LeakyRelu(0.2)) }, []float64{0.8, -0.4}},
+		{"neg", func(v []*Value) *Value { return v[0].Neg() }, []float64{0.3}},
+		{"sub", func(v []*Value) *Value { return v[0].Sub(v[1]) }, []float64{0.3, 1.9}},
+		{"div", func(v []*Value) *Value { return v[0].Div(v[1]) }, []float64{0.3, -1.9}},
+		{"exp", func(v []*Value) *Value { return v[0].Exp() }, []float64{0.7}},
+		{"log", func(v []*Value) *Value { return v[0].Log() }, []float64{0.7}},
+		{"sqrt", func(v []*Value) *Value { return v[0].Sqrt() }, []float64{0.7}},
+		{"abs", func(v []*Value) *Value { return v[0].Abs().Add(v[1].Abs()) }, []float64{0.7, -1.2}},
+		{"sin", func(v []*Value) *Value { return v[0].Sin() }, []float64{0.7}},
+		{"cos", func(v []*Value) *Value { return v[0].Cos() }, []float64{0.7}},
+		{"tanh", func(v []*Value) *Value { return v[0].Tanh() }, []float64{0.7}},
+		{"sigmoid", func(v []*Value) *Value { return v[0].Sigmoid() }, []float64{-0.7}},
+		{"silu", func(v []*Value) *Value { return v[0].Silu() }, []float64{-0.7}},
+		{"normal cdf", func(v []*Value) *Value { return v[0].NormalCDF() }, []float64{-0.7}},
+		{"gelu", func(v []*Value) *Value { return v[0].Gelu() }, []float64{-0.7}},
+		{"softplus", func(v []*Value) *Value { return v[0].Softplus() }, []float64{-0.7}},
+		{"logsigmoid", func(v []*Value) *Value { return v[0].LogSigmoid() }, []float64{-0.7}},
+		{"sum", func(v []*Value) *Value { return Sum(v[0], v[1], v[0].Mul(v[2])) }, []float64{0.5, -1.5, 2.0}},
+		{"mean", func(v []*Value) *Value { return Mean(v[0], v[1].Mul(v[1]), v[2]) }, []float64{0.5, -1.5, 2.0}},
+		{"composite", func(v []*Value) *Value {
+			// reuses inputs along several paths
+			a, b, c := v[0], v[1], v[2]
+			return a.Mul(b).Add(c).Tanh().Pow(2).Add(a.Div(c.Exp())).Sub(b.Sigmoid().Log())
+		}, []float64{0.5, -1.5, 0.25}},
+	}
+	for _, tt := range tests {
+		results := GradCheck(tt.f, tt.x, 1e-6)
+		if err := MaxRelError(results); err > 1e-6 {
+			t.Errorf("%s: max relative error %g, results %+v", tt.name, err, results)
+		}
+	}
+}
+
+func TestGradCheckDetectsWrongGradient(t *testing.T) {
+	// an op whose backward closure is deliberately off by a factor of two
+	double := func(v []*Value) *Value {
+		out := makeValue(v[0].Data*v[0].Data, []*Value{v[0]}, "bad^2")
+		out.backward = func() {
+			v[0].Grad += v[0].Data * out.Grad
+		}
+		return out
+	}
+	results := GradCheck(double, []float64{1.5}, 1e-6)
+	if err := MaxRelError(results); err < 0.4 {
+		t.Errorf("expected a large relative error, got %g", err)
+	}
+}
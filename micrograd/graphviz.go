@@ -0,0 +1,116 @@
+package micrograd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	svg "github.com/ajstarks/svgo"
+)
+
+// WriteDot writes the graph rooted at v in Graphviz DOT format, one record node
+// per value showing its op, data and grad (like draw_dot in the original micrograd).
+// Render it with e.g. `dot -Tsvg graph.dot -o graph.svg`.
+func (v *Value) WriteDot(w io.Writer) error {
+	topo := v.topo()
+	ids := make(map[*Value]int, len(topo))
+	for i, node := range topo {
+		ids[node] = i
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph G {")
+	fmt.Fprintln(bw, "  rankdir=LR;")
+	fmt.Fprintln(bw, "  node [shape=record];")
+	for i, node := range topo {
+		fmt.Fprintf(bw, "  n%d [label=\"{ %s | data %.4f | grad %.4f }\"];\n", i, dotEscape(node.op), node.Data, node.Grad)
+	}
+	for i, node := range topo {
+		for _, child := range node.prev {
+			fmt.Fprintf(bw, "  n%d -> n%d;\n", ids[child], i)
+		}
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+func dotEscape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "{", `\{`, "}", `\}`, "|", `\|`, "<", `\<`, ">", `\>`)
+	return r.Replace(s)
+}
+
+const (
+	svgNodeWidth  = 150
+	svgNodeHeight = 54
+	svgColumnGap  = 60
+	svgRowGap     = 20
+	svgMargin     = 20
+)
+
+// WriteSVG renders the graph rooted at v directly to SVG so small graphs can be
+// inspected without installing graphviz. The layout is a simple left to right
+// layering: leaves go in the first column and every other value goes one column
+// to the right of its deepest input.
+func (v *Value) WriteSVG(w io.Writer) error {
+	topo := v.topo()
+
+	// topo lists children before parents, so ranks can be assigned in a single pass
+	rank := make(map[*Value]int, len(topo))
+	row := make(map[*Value]int, len(topo))
+	columns := []int{}
+	for _, node := range topo {
+		r := 0
+		for _, child := range node.prev {
+			r = max(r, rank[child]+1)
+		}
+		if r == len(columns) {
+			columns = append(columns, 0)
+		}
+		rank[node] = r
+		row[node] = columns[r]
+		columns[r]++
+	}
+	rows := 0
+	for _, n := range columns {
+		rows = max(rows, n)
+	}
+
+	position := func(node *Value) (int, int) {
+		x := svgMargin + rank[node]*(svgNodeWidth+svgColumnGap)
+		y := svgMargin + row[node]*(svgNodeHeight+svgRowGap)
+		return x, y
+	}
+
+	bw := bufio.NewWriter(w)
+	canvas := svg.New(bw)
+	width := 2*svgMargin + len(columns)*svgNodeWidth + (len(columns)-1)*svgColumnGap
+	height := 2*svgMargin + rows*svgNodeHeight + (rows-1)*svgRowGap
+	canvas.Start(width, height)
+	canvas.Rect(0, 0, width, height, "fill:white")
+
+	for _, node := range topo {
+		x, y := position(node)
+		for _, child := range node.prev {
+			cx, cy := position(child)
+			canvas.Line(cx+svgNodeWidth, cy+svgNodeHeight/2, x, y+svgNodeHeight/2, "stroke:gray;stroke-width:1")
+		}
+	}
+	for _, node := range topo {
+		x, y := position(node)
+		canvas.Rect(x, y, svgNodeWidth, svgNodeHeight, "fill:#f4f4f4;stroke:black")
+		textStyle := "font-family:monospace;font-size:11px"
+		op := node.op
+		if op == "" {
+			op = "leaf"
+			if node.constant {
+				op = "const"
+			}
+		}
+		canvas.Text(x+6, y+15, op, textStyle+";font-weight:bold")
+		canvas.Text(x+6, y+31, fmt.Sprintf("data %.4f", node.Data), textStyle)
+		canvas.Text(x+6, y+47, fmt.Sprintf("grad %.4f", node.Grad), textStyle)
+	}
+	canvas.End()
+	return bw.Flush()
+}
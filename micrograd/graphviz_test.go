@@ -0,0 +1,51 @@
+package micrograd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteDot(t *testing.T) {
+	a := NewValue(2.0)
+	b := NewValue(-3.0)
+	c := a.Mul(b).Add(a)
+	c.Backward()
+
+	var buf bytes.Buffer
+	if err := c.WriteDot(&buf); err != nil {
+		t.Fatal(err)
+	}
+	dot := buf.String()
+	if !strings.HasPrefix(dot, "digraph G {") {
+		t.Errorf("missing digraph header:\n%s", dot)
+	}
+	// a, b, a*b and the final sum
+	if n := strings.Count(dot, "[label="); n != 4 {
+		t.Errorf("got %d nodes, want 4:\n%s", n, dot)
+	}
+	if n := strings.Count(dot, "->"); n != 4 {
+		t.Errorf("got %d edges, want 4:\n%s", n, dot)
+	}
+	if !strings.Contains(dot, "data -4.0000 | grad 1.0000") {
+		t.Errorf("root node not labelled with data and grad:\n%s", dot)
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	a := NewValue(2.0)
+	c := a.Mul(a).Tanh()
+	c.Backward()
+
+	var buf bytes.Buffer
+	if err := c.WriteSVG(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "<svg") || !strings.HasSuffix(strings.TrimSpace(out), "</svg>") {
+		t.Errorf("not an svg document:\n%s", out)
+	}
+	if n := strings.Count(out, ">tanh<"); n != 1 {
+		t.Errorf("got %d tanh nodes, want 1", n)
+	}
+}
@@ -0,0 +1,155 @@
+package micrograd
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Gradients returns the derivatives of output with respect to inputs as Values
+// recorded in the graph (create_graph in PyTorch), so they can be used in a
+// loss and differentiated again with Backward or another call to Gradients.
+// The Grad fields of the graph are left untouched. Inputs that output does not
+// depend on get a constant 0.
+//
+// The slopes of ReLU, LeakyReLU and Abs are constants picked from the Data of
+// their inputs, so the gradient graphs cannot be compiled into a Tape.
+func Gradients(output *Value, inputs ...*Value) ([]*Value, error) {
+	if output.nograd {
+		return nil, errors.New("cannot differentiate a value computed without gradients")
+	}
+	topo := output.topo()
+	grads := make(map[*Value]*Value, len(topo))
+	grads[output] = Constant(1.0)
+	accumulate := func(v, g *Value) {
+		if prev, ok := grads[v]; ok {
+			grads[v] = prev.Add(g)
+		} else {
+			grads[v] = g
+		}
+	}
+
+	for i := len(topo) - 1; i >= 0; i-- {
+		v := topo[i]
+		g, ok := grads[v]
+		if !ok || len(v.prev) == 0 {
+			continue
+		}
+		code, err := opcodeOf(v)
+		if err != nil {
+			return nil, err
+		}
+		if code == opStopGradient {
+			continue
+		}
+		if code == opSum {
+			for _, child := range v.prev {
+				accumulate(child, g)
+			}
+			continue
+		}
+		if code == opAdd || code == opMul {
+			a, b := v.prev[0], v.prev[1]
+			if code == opAdd {
+				accumulate(a, g)
+				accumulate(b, g)
+			} else {
+				accumulate(a, g.Mul(b))
+				accumulate(b, g.Mul(a))
+			}
+			continue
+		}
+		accumulate(v.prev[0], g.Mul(localGradient(code, v, v.prev[0])))
+	}
+
+	out := make([]*Value, len(inputs))
+	for i, in := range inputs {
+		if g, ok := grads[in]; ok {
+			out[i] = g
+		} else {
+			out[i] = Constant(0.0)
+		}
+	}
+	return out, nil
+}
+
+// localGradient is d out / d x for the unary op that computed out from x,
+// built from Values so that it can be differentiated in turn
+func localGradient(code opcode, out, x *Value) *Value {
+	switch code {
+	case opPow:
+		return x.Pow(out.arg - 1).Mul(Constant(out.arg))
+	case opRelu, opLeakyRelu, opAbs:
+		// piecewise linear ops have a constant slope on either side of 0
+		slope := 1.0
+		if x.Data <= 0 {
+			switch code {
+			case opRelu:
+				slope = 0
+			case opLeakyRelu:
+				slope = out.arg
+			case opAbs:
+				if x.Data < 0 {
+					slope = -1
+				} else {
+					slope = 0
+				}
+			}
+		}
+		return dataConstant(slope)
+	case opExp:
+		return out
+	case opLog:
+		return x.Pow(-1)
+	case opSqrt:
+		return out.Mul(Constant(2)).Pow(-1)
+	case opSin:
+		return x.Cos()
+	case opCos:
+		return x.Sin().Neg()
+	case opTanh:
+		return Constant(1).Sub(out.Mul(out))
+	case opSigmoid:
+		return out.Mul(Constant(1).Sub(out))
+	case opSilu:
+		s := x.Sigmoid()
+		return s.Mul(Constant(1).Add(x.Mul(Constant(1).Sub(s))))
+	case opGelu:
+		return x.NormalCDF().Add(x.Mul(standardNormalPDF(x)))
+	case opNormalCDF:
+		return standardNormalPDF(x)
+	case opSoftplus:
+		return x.Sigmoid()
+	case opLogSigmoid:
+		return x.Neg().Sigmoid()
+	}
+	panic(fmt.Sprintf("no local gradient for op %q", out.op))
+}
+
+// standardNormalPDF is exp(-x^2 / 2) / sqrt(2 pi) built from Values
+func standardNormalPDF(x *Value) *Value {
+	return x.Mul(x).Mul(Constant(-0.5)).Exp().Mul(Constant(normalPDF(0)))
+}
+
+// dataConstant makes a constant picked from the Data of the graph being built
+func dataConstant(data float64) *Value {
+	v := Constant(data)
+	v.dataDependent = true
+	return v
+}
+
+// HessianDiagonal returns the second derivatives d^2 output / d input_i^2
+func HessianDiagonal(output *Value, inputs ...*Value) ([]float64, error) {
+	grads, err := Gradients(output, inputs...)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]float64, len(inputs))
+	for i, g := range grads {
+		second, err := Gradients(g, inputs[i])
+		if err != nil {
+			return nil, err
+		}
+		out[i] = second[0].Data
+	}
+	return out, nil
+}
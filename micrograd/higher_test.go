@@ -0,0 +1,126 @@
+package micrograd
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGradientsMatchBackward(t *testing.T) {
+	x := []*Value{NewValue(0.7), NewValue(-1.3), NewValue(2.1)}
+	f := everyOp(x)
+	grads, err := Gradients(f, x...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Backward()
+	for i := range x {
+		if math.Abs(grads[i].Data-x[i].Grad) > 1e-12 {
+			t.Errorf("input %d: Gradients %v, Backward %v", i, grads[i].Data, x[i].Grad)
+		}
+	}
+}
+
+func TestSecondDerivatives(t *testing.T) {
+	// check d/dx of the recorded first derivative against finite differences, for every op
+	for _, tt := range []struct {
+		name string
+		f    func([]*Value) *Value
+		x    []float64
+	}{
+		{"every op", everyOp, []float64{0.7, -1.3, 2.1}},
+		{"mlp", func(v []*Value) *Value {
+			mlp := NewMLP(3, []int{4, 1}, WithSeed(3), WithHiddenActivation(Tanh))
+			return mlp.Forward(v)[0]
+		}, []float64{0.2, -0.4, 0.9}},
+	} {
+		for i := range tt.x {
+			results := GradCheck(func(v []*Value) *Value {
+				grads, err := Gradients(tt.f(v), v...)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return grads[i]
+			}, tt.x, 1e-5)
+			if err := MaxRelError(results); err > 1e-5 {
+				t.Errorf("%s d/dx%d: max relative error %g, results %+v", tt.name, i, err, results)
+			}
+		}
+	}
+}
+
+func TestHessianDiagonal(t *testing.T) {
+	// f = x^3 * y + sin(y) has d2f/dx2 = 6xy and d2f/dy2 = -sin(y)
+	x, y := NewValue(1.5), NewValue(-0.5)
+	f := x.Pow(3).Mul(y).Add(y.Sin())
+	h, err := HessianDiagonal(f, x, y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []float64{6 * 1.5 * -0.5, -math.Sin(-0.5)}
+	for i := range want {
+		if math.Abs(h[i]-want[i]) > 1e-12 {
+			t.Errorf("h[%d] = %v, want %v", i, h[i], want[i])
+		}
+	}
+}
+
+func TestGradientPenalty(t *testing.T) {
+	// loss = f + (df/dx)^2 with f = x^2, so loss = x^2 + 4x^2 and dloss/dx = 10x
+	x := NewValue(0.3)
+	f := x.Mul(x)
+	grads, err := Gradients(f, x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loss := f.Add(grads[0].Pow(2))
+	loss.Backward()
+	if math.Abs(x.Grad-3.0) > 1e-12 {
+		t.Errorf("got %v, want 3", x.Grad)
+	}
+}
+
+func TestGradientsUnreachableInput(t *testing.T) {
+	x, y := NewValue(1), NewValue(2)
+	grads, err := Gradients(x.Exp(), y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if grads[0].Data != 0 {
+		t.Errorf("got %v, want 0", grads[0].Data)
+	}
+}
+
+func TestGradientsConstants(t *testing.T) {
+	x := NewValue(0.5)
+	grads, err := Gradients(x.Relu().Mul(x).Add(x.Abs()), x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the slopes are constants picked from x.Data, which a tape cannot follow
+	if _, err := Compile(grads[0]); err == nil {
+		t.Error("compiled a gradient graph with data dependent slopes")
+	}
+	simple, _, err := Simplify(grads[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Compile(simple); err == nil {
+		t.Error("compiled a simplified gradient graph with data dependent slopes")
+	}
+
+	// the literals of the derivatives are constants, the only other leaf is x
+	tanh, err := Gradients(x.Tanh().Mul(NewValue(3)), x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := Analyze(tanh[0]); s.Leaves-s.Constants != 2 {
+		t.Errorf("%d leaves are not constants, want x and the factor 3", s.Leaves-s.Constants)
+	}
+	if _, err := Compile(tanh[0]); err != nil {
+		t.Errorf("smooth gradient graph: %v", err)
+	}
+
+	if _, err := Gradients(NoGradValue(1).Mul(x), x); err == nil {
+		t.Error("expected an error for a value computed without gradients")
+	}
+}
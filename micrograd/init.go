@@ -0,0 +1,58 @@
+package micrograd
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Initializer draws the initial value of one weight of a neuron with fanIn
+// inputs that sits in a layer of fanOut neurons
+type Initializer func(r *rand.Rand, fanIn, fanOut int) float64
+
+// Uniform draws from U(-1, 1), the default of the original micrograd
+func Uniform(r *rand.Rand, fanIn, fanOut int) float64 {
+	return r.Float64()*2 - 1
+}
+
+func Zeros(r *rand.Rand, fanIn, fanOut int) float64 {
+	return 0
+}
+
+// XavierUniform (Glorot) draws from U(-a, a) with a = sqrt(6 / (fanIn + fanOut)),
+// a good fit for tanh and sigmoid layers
+func XavierUniform(r *rand.Rand, fanIn, fanOut int) float64 {
+	limit := math.Sqrt(6.0 / float64(fanIn+fanOut))
+	return (r.Float64()*2 - 1) * limit
+}
+
+// XavierNormal (Glorot) draws from N(0, 2 / (fanIn + fanOut))
+func XavierNormal(r *rand.Rand, fanIn, fanOut int) float64 {
+	return r.NormFloat64() * math.Sqrt(2.0/float64(fanIn+fanOut))
+}
+
+// KaimingUniform (He) draws from U(-a, a) with a = sqrt(6 / fanIn), a good fit for ReLU layers
+func KaimingUniform(r *rand.Rand, fanIn, fanOut int) float64 {
+	limit := math.Sqrt(6.0 / float64(fanIn))
+	return (r.Float64()*2 - 1) * limit
+}
+
+// KaimingNormal (He) draws from N(0, 2 / fanIn)
+func KaimingNormal(r *rand.Rand, fanIn, fanOut int) float64 {
+	return r.NormFloat64() * math.Sqrt(2.0/float64(fanIn))
+}
+
+// globalSource draws from the top level math/rand functions, so networks built
+// without WithRand or WithSeed behave as before
+type globalSource struct{}
+
+func (globalSource) Int63() int64 {
+	return rand.Int63()
+}
+
+func (globalSource) Uint64() uint64 {
+	return rand.Uint64()
+}
+
+func (globalSource) Seed(int64) {
+	panic("the global random source cannot be seeded, use WithSeed instead")
+}
@@ -0,0 +1,89 @@
+package micrograd
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func weights(mlp *MLP) []float64 {
+	params := mlp.Parameters()
+	out := make([]float64, len(params))
+	for i, p := range params {
+		out[i] = p.Data
+	}
+	return out
+}
+
+func TestWithSeedIsReproducible(t *testing.T) {
+	a := weights(NewMLP(2, []int{4, 4, 1}, WithSeed(7)))
+	b := weights(NewMLP(2, []int{4, 4, 1}, WithSeed(7)))
+	c := weights(NewMLP(2, []int{4, 4, 1}, WithSeed(8)))
+	same := true
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("weight %d differs with the same seed: %v != %v", i, a[i], b[i])
+		}
+		same = same && a[i] == c[i]
+	}
+	if same {
+		t.Error("different seeds built identical networks")
+	}
+}
+
+func TestWithRand(t *testing.T) {
+	a := weights(NewMLP(3, []int{2}, WithRand(rand.New(rand.NewSource(1)))))
+	b := weights(NewMLP(3, []int{2}, WithSeed(1)))
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("weight %d: WithRand and WithSeed disagree: %v != %v", i, a[i], b[i])
+		}
+	}
+}
+
+func TestInitializers(t *testing.T) {
+	const fanIn, fanOut = 50, 30
+	tests := []struct {
+		name  string
+		init  Initializer
+		limit float64 // largest allowed absolute value, 0 if unbounded
+		std   float64 // expected standard deviation
+	}{
+		{"uniform", Uniform, 1, 1 / math.Sqrt(3)},
+		{"zeros", Zeros, 0, 0},
+		{"xavier uniform", XavierUniform, math.Sqrt(6.0 / (fanIn + fanOut)), math.Sqrt(2.0 / (fanIn + fanOut))},
+		{"xavier normal", XavierNormal, 0, math.Sqrt(2.0 / (fanIn + fanOut))},
+		{"kaiming uniform", KaimingUniform, math.Sqrt(6.0 / fanIn), math.Sqrt(2.0 / fanIn)},
+		{"kaiming normal", KaimingNormal, 0, math.Sqrt(2.0 / fanIn)},
+	}
+	for _, tt := range tests {
+		layer := NewLayer(fanIn, fanOut, ReLU, WithSeed(3), WithInit(tt.init))
+		sum, sumSq, n := 0.0, 0.0, 0.0
+		for _, neuron := range layer.neurons {
+			if neuron.b.Data != 0 {
+				t.Errorf("%s: bias %v, want 0", tt.name, neuron.b.Data)
+			}
+			for _, w := range neuron.w {
+				if tt.limit > 0 && math.Abs(w.Data) > tt.limit {
+					t.Errorf("%s: weight %v outside +-%v", tt.name, w.Data, tt.limit)
+				}
+				sum += w.Data
+				sumSq += w.Data * w.Data
+				n++
+			}
+		}
+		std := math.Sqrt(sumSq/n - (sum/n)*(sum/n))
+		if math.Abs(std-tt.std) > 0.1*tt.std+1e-12 {
+			t.Errorf("%s: standard deviation %v, want about %v", tt.name, std, tt.std)
+		}
+	}
+}
+
+func TestCustomInitializer(t *testing.T) {
+	constant := func(r *rand.Rand, fanIn, fanOut int) float64 { return 0.5 }
+	for _, w := range weights(NewMLP(2, []int{3}, WithInit(constant))) {
+		if w != 0.5 && w != 0 {
+			t.Errorf("got weight %v, want 0.5 (or a 0 bias)", w)
+		}
+	}
+}
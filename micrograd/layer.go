@@ -1,41 +1,159 @@
 package micrograd
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
+// Layer is a row of neurons sharing their inputs. It can normalize the weighted
+// sums of its neurons with a BatchNorm before their activation, and apply
+// Dropout to its outputs.
 type Layer struct {
 	neurons []*Neuron
+	norm    *BatchNorm
+	dropout *Dropout
 }
 
-func NewLayer(nin, nout int, nonlin bool) *Layer {
+func NewLayer(nin, nout int, act Activation, opts ...Option) *Layer {
+	cfg := newConfig(opts)
+	layer := newLayer(nin, nout, act, cfg)
+	layer.regularize(cfg)
+	return layer
+}
+
+func newLayer(nin, nout int, act Activation, cfg *config) *Layer {
 	layer := &Layer{neurons: make([]*Neuron, nout)}
 	for i := range layer.neurons {
-		layer.neurons[i] = NewNeuron(nin, nonlin)
+		layer.neurons[i] = newNeuron(nin, nout, act, cfg)
 	}
 	return layer
 }
 
+// regularize adds the batch norm and dropout asked for by cfg
+func (l *Layer) regularize(cfg *config) {
+	if cfg.batchNorm {
+		l.norm = NewBatchNorm(len(l.neurons))
+	}
+	if cfg.dropout > 0 {
+		l.dropout = NewDropout(cfg.dropout, cfg.rng)
+	}
+}
+
+// Forward computes the outputs of the layer for a single example. A BatchNorm
+// uses its running statistics then, since one example has no batch statistics,
+// ForwardBatch is needed to train it.
 func (l *Layer) Forward(x []*Value) []*Value {
 	out := make([]*Value, len(l.neurons))
+	if l.norm == nil {
+		for i := range out {
+			out[i] = l.neurons[i].Forward(x)
+		}
+	} else {
+		for i := range out {
+			out[i] = l.neurons[i].linear(x)
+		}
+		out = l.activate(l.norm.Forward(out))
+	}
+	if l.dropout != nil {
+		out = l.dropout.Forward(out)
+	}
+	return out
+}
+
+// ForwardBatch computes the outputs of the layer for a batch of examples
+func (l *Layer) ForwardBatch(xs [][]*Value) [][]*Value {
+	out := make([][]*Value, len(xs))
+	if l.norm == nil {
+		for i, x := range xs {
+			out[i] = l.Forward(x)
+		}
+		return out
+	}
+	for i, x := range xs {
+		out[i] = make([]*Value, len(l.neurons))
+		for k, n := range l.neurons {
+			out[i][k] = n.linear(x)
+		}
+	}
+	out = l.norm.ForwardBatch(out)
 	for i := range out {
-		out[i] = l.neurons[i].Forward(x)
+		out[i] = l.activate(out[i])
+		if l.dropout != nil {
+			out[i] = l.dropout.Forward(out[i])
+		}
 	}
 	return out
 }
 
+// activate applies the activation of every neuron to its (normalized) sum
+func (l *Layer) activate(sums []*Value) []*Value {
+	for i := range sums {
+		sums[i] = l.neurons[i].act.Apply(sums[i])
+	}
+	return sums
+}
+
 func (l *Layer) Parameters() []*Value {
 	parameters := make([]*Value, 0)
 	for _, neuron := range l.neurons {
 		parameters = append(parameters, neuron.Parameters()...)
 	}
+	if l.norm != nil {
+		parameters = append(parameters, l.norm.Parameters()...)
+	}
 	return parameters
 }
 
+// NamedParameters prefixes the names of the neurons with "neurons.<index>" and
+// those of the batch norm with "norm"
+func (l *Layer) NamedParameters() []NamedParameter {
+	parameters := make([]NamedParameter, 0)
+	for i, neuron := range l.neurons {
+		parameters = append(parameters, prefixed(fmt.Sprintf("neurons.%d", i), neuron.NamedParameters())...)
+	}
+	if l.norm != nil {
+		parameters = append(parameters, prefixed("norm", l.norm.NamedParameters())...)
+	}
+	return parameters
+}
+
+func (l *Layer) namedBuffers() []namedBuffer {
+	if l.norm == nil {
+		return nil
+	}
+	return prefixedBuffers("norm", l.norm.namedBuffers())
+}
+
 func (l *Layer) ZeroGrad() {
-	for _, p := range l.Parameters() {
-		p.Grad = 0.0
+	zeroGrad(l.Parameters())
+}
+
+// SetTraining switches the batch norm and the dropout of the layer between
+// training and evaluation mode
+func (l *Layer) SetTraining(training bool) {
+	if l.norm != nil {
+		l.norm.SetTraining(training)
+	}
+	if l.dropout != nil {
+		l.dropout.SetTraining(training)
 	}
 }
 
 func (l *Layer) String() string {
-	return fmt.Sprintf("Layer {in: %v, out: %v}", len(l.neurons[0].Parameters())-1, len(l.neurons))
+	var extra strings.Builder
+	if l.norm != nil {
+		fmt.Fprintf(&extra, ", norm: %v", l.norm)
+	}
+	if l.dropout != nil {
+		fmt.Fprintf(&extra, ", dropout: %v", l.dropout.P)
+	}
+	return fmt.Sprintf("Layer {in: %v, out: %v, act: %v%s}", l.nin(), len(l.neurons), l.activation(), extra.String())
+}
+
+func (l *Layer) nin() int {
+	return len(l.neurons[0].w)
+}
+
+func (l *Layer) activation() Activation {
+	return l.neurons[0].act
 }
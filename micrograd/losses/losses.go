@@ -0,0 +1,176 @@
+// Package losses implements loss functions over micrograd values
+package losses
+
+import "vdanciu_lang_model/micrograd"
+
+// Reduction is how the per example losses are combined into a single value
+type Reduction int
+
+const (
+	Mean Reduction = iota
+	Sum
+)
+
+// Option configures a loss function
+type Option func(*config)
+
+type config struct {
+	reduction Reduction
+	penalties []penalty
+}
+
+// penalty is a weighted L1 or L2 regularization term over a set of parameters
+type penalty struct {
+	alpha  float64
+	params []*micrograd.Value
+	l1     bool
+}
+
+func WithReduction(r Reduction) Option {
+	return func(c *config) {
+		c.reduction = r
+	}
+}
+
+// WithL2 adds alpha * sum(p^2) over params to the loss
+func WithL2(alpha float64, params []*micrograd.Value) Option {
+	return func(c *config) {
+		c.penalties = append(c.penalties, penalty{alpha: alpha, params: params})
+	}
+}
+
+// WithL1 adds alpha * sum(|p|) over params to the loss
+func WithL1(alpha float64, params []*micrograd.Value) Option {
+	return func(c *config) {
+		c.penalties = append(c.penalties, penalty{alpha: alpha, params: params, l1: true})
+	}
+}
+
+// Hinge is the max-margin loss max(0, 1 - y*s) used by SVMs, with targets in {-1, 1}
+func Hinge(scores []*micrograd.Value, targets []float64, opts ...Option) *micrograd.Value {
+	checkSizes(len(scores), len(targets))
+	losses := make([]*micrograd.Value, len(scores))
+	for i, s := range scores {
+		losses[i] = micrograd.Constant(1.0).Sub(s.Mul(micrograd.Constant(targets[i]))).Relu()
+	}
+	return reduce(losses, opts)
+}
+
+// MSE is the mean squared error (p - y)^2
+func MSE(preds []*micrograd.Value, targets []float64, opts ...Option) *micrograd.Value {
+	checkSizes(len(preds), len(targets))
+	losses := make([]*micrograd.Value, len(preds))
+	for i, p := range preds {
+		losses[i] = diff(p, targets[i]).Pow(2)
+	}
+	return reduce(losses, opts)
+}
+
+// MAE is the mean absolute error |p - y|
+func MAE(preds []*micrograd.Value, targets []float64, opts ...Option) *micrograd.Value {
+	checkSizes(len(preds), len(targets))
+	losses := make([]*micrograd.Value, len(preds))
+	for i, p := range preds {
+		losses[i] = diff(p, targets[i]).Abs()
+	}
+	return reduce(losses, opts)
+}
+
+// Huber is quadratic for errors smaller than delta and linear beyond, which
+// makes it less sensitive to outliers than MSE. It is computed as
+// 0.5*q^2 + delta*(|d| - q) with q = min(|d|, delta), so that the graph does not
+// depend on which side of delta the error falls and can be replayed on a Tape.
+func Huber(preds []*micrograd.Value, targets []float64, delta float64, opts ...Option) *micrograd.Value {
+	checkSizes(len(preds), len(targets))
+	losses := make([]*micrograd.Value, len(preds))
+	for i, p := range preds {
+		abs := diff(p, targets[i]).Abs()
+		excess := abs.Sub(micrograd.Constant(delta)).Relu()
+		q := abs.Sub(excess)
+		losses[i] = q.Pow(2).Mul(micrograd.Constant(0.5)).Add(excess.Mul(micrograd.Constant(delta)))
+	}
+	return reduce(losses, opts)
+}
+
+// BCEWithLogits is the binary cross-entropy of sigmoid(logit) with targets in [0, 1].
+// It is computed as softplus(x) - y*x, which stays finite for large logits.
+func BCEWithLogits(logits []*micrograd.Value, targets []float64, opts ...Option) *micrograd.Value {
+	checkSizes(len(logits), len(targets))
+	losses := make([]*micrograd.Value, len(logits))
+	for i, x := range logits {
+		losses[i] = x.Softplus().Sub(x.Mul(micrograd.Constant(targets[i])))
+	}
+	return reduce(losses, opts)
+}
+
+// SoftmaxCrossEntropy is the negative log likelihood of the class labels under
+// the softmax of the logits; logits[i] holds the outputs of MLP.Forward for example i
+func SoftmaxCrossEntropy(logits [][]*micrograd.Value, labels []int, opts ...Option) *micrograd.Value {
+	checkSizes(len(logits), len(labels))
+	losses := make([]*micrograd.Value, len(logits))
+	for i, l := range logits {
+		if labels[i] < 0 || labels[i] >= len(l) {
+			panic("label out of range")
+		}
+		losses[i] = LogSumExp(l).Sub(l[labels[i]])
+	}
+	return reduce(losses, opts)
+}
+
+// LogSumExp computes log(sum(exp(x))), shifted by the largest input so exp cannot
+// overflow. The largest input is part of the graph, built as a + relu(b - a)
+// behind StopGradient, so a Tape replaying it follows the new inputs.
+func LogSumExp(x []*micrograd.Value) *micrograd.Value {
+	m := x[0]
+	for _, v := range x[1:] {
+		m = m.Add(v.Sub(m).Relu())
+	}
+	shift := m.StopGradient()
+	exps := make([]*micrograd.Value, len(x))
+	for i, v := range x {
+		exps[i] = v.Sub(shift).Exp()
+	}
+	return micrograd.Sum(exps...).Log().Add(shift)
+}
+
+func diff(p *micrograd.Value, target float64) *micrograd.Value {
+	return p.Add(micrograd.Constant(-target))
+}
+
+// reduce combines the per example losses and adds the regularization penalties
+func reduce(losses []*micrograd.Value, opts []Option) *micrograd.Value {
+	cfg := &config{reduction: Mean}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	var total *micrograd.Value
+	switch cfg.reduction {
+	case Mean:
+		total = micrograd.Mean(losses...)
+	case Sum:
+		total = micrograd.Sum(losses...)
+	default:
+		panic("unknown reduction")
+	}
+	for _, p := range cfg.penalties {
+		terms := make([]*micrograd.Value, len(p.params))
+		for i, param := range p.params {
+			if p.l1 {
+				terms[i] = param.Abs()
+			} else {
+				terms[i] = param.Mul(param)
+			}
+		}
+		total = total.Add(micrograd.Sum(terms...).Mul(micrograd.Constant(p.alpha)))
+	}
+	return total
+}
+
+func checkSizes(preds, targets int) {
+	if preds != targets {
+		panic("predictions and targets size mismatch")
+	}
+	if preds == 0 {
+		panic("empty batch")
+	}
+}
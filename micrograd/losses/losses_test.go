@@ -0,0 +1,118 @@
+package losses
+
+import (
+	"math"
+	"testing"
+	"vdanciu_lang_model/micrograd"
+)
+
+func values(xs ...float64) []*micrograd.Value {
+	out := make([]*micrograd.Value, len(xs))
+	for i, x := range xs {
+		out[i] = micrograd.NewValue(x)
+	}
+	return out
+}
+
+func TestLosses(t *testing.T) {
+	tests := []struct {
+		name string
+		loss *micrograd.Value
+		want float64
+	}{
+		{"hinge", Hinge(values(2.0, 0.5, -1.0), []float64{1, 1, 1}), (0 + 0.5 + 2.0) / 3},
+		{"hinge sum", Hinge(values(2.0, 0.5, -1.0), []float64{1, 1, 1}, WithReduction(Sum)), 2.5},
+		{"mse", MSE(values(1.0, 2.0), []float64{0.0, 4.0}), (1.0 + 4.0) / 2},
+		{"mae", MAE(values(1.0, 2.0), []float64{0.0, 4.0}), (1.0 + 2.0) / 2},
+		{"huber", Huber(values(0.5, 3.0), []float64{0.0, 0.0}, 1.0), (0.125 + 2.5) / 2},
+		{"bce", BCEWithLogits(values(0.0, 100.0), []float64{1, 1}), math.Ln2 / 2},
+		{"bce large negative", BCEWithLogits(values(-1000.0), []float64{1}), 1000},
+		{"softmax ce", SoftmaxCrossEntropy([][]*micrograd.Value{values(1, 1, 1, 1)}, []int{2}), math.Log(4)},
+		{"softmax ce large logits", SoftmaxCrossEntropy([][]*micrograd.Value{values(1000, 0)}, []int{0}), 0},
+		{"l2", MSE(values(1.0), []float64{1.0}, WithL2(0.5, values(2.0, -1.0))), 0.5 * 5},
+		{"l1", MSE(values(1.0), []float64{1.0}, WithL1(0.5, values(2.0, -1.0))), 0.5 * 3},
+	}
+	for _, tt := range tests {
+		if math.Abs(tt.loss.Data-tt.want) > 1e-9 {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.loss.Data, tt.want)
+		}
+	}
+}
+
+func TestLossGradients(t *testing.T) {
+	labels := []int{2, 0}
+	tests := []struct {
+		name string
+		f    func([]*micrograd.Value) *micrograd.Value
+	}{
+		{"hinge", func(v []*micrograd.Value) *micrograd.Value { return Hinge(v[:2], []float64{1, -1}) }},
+		{"mse", func(v []*micrograd.Value) *micrograd.Value { return MSE(v, []float64{0.1, 0.2, 0.3, 0.4, 0.5, 0.6}) }},
+		{"huber", func(v []*micrograd.Value) *micrograd.Value { return Huber(v[:3], []float64{0, 0, 0}, 0.7) }},
+		{"bce", func(v []*micrograd.Value) *micrograd.Value { return BCEWithLogits(v[:3], []float64{1, 0, 0.3}) }},
+		{"softmax ce", func(v []*micrograd.Value) *micrograd.Value {
+			return SoftmaxCrossEntropy([][]*micrograd.Value{v[:3], v[3:]}, labels, WithL2(0.1, v))
+		}},
+	}
+	x := []float64{0.3, -1.2, 0.9, 1.5, -0.4, 0.05}
+	for _, tt := range tests {
+		results := micrograd.GradCheck(tt.f, x, 1e-6)
+		if err := micrograd.MaxRelError(results); err > 1e-5 {
+			t.Errorf("%s: max relative error %g, results %+v", tt.name, err, results)
+		}
+	}
+}
+
+func TestLiteralsAreConstants(t *testing.T) {
+	preds := values(0.3, -1.2, 0.9)
+	for name, loss := range map[string]*micrograd.Value{
+		"hinge": Hinge(preds, []float64{1, -1, 1}),
+		"mse":   MSE(preds, []float64{0, 1, 2}, WithL2(0.1, preds)),
+		"huber": Huber(preds, []float64{0, 0, 0}, 1),
+		"bce":   BCEWithLogits(preds, []float64{1, 0, 1}),
+	} {
+		s := micrograd.Analyze(loss)
+		if leaves := s.Leaves - s.Constants; leaves != len(preds) {
+			t.Errorf("%s: %d leaves are not constants, want %d", name, leaves, len(preds))
+		}
+	}
+}
+
+func TestLossesReplayOnTape(t *testing.T) {
+	huberInputs, ceInputs := values(0.5), values(0, 0)
+	tests := []struct {
+		name   string
+		inputs []*micrograd.Value
+		loss   func(x []*micrograd.Value) *micrograd.Value
+		next   []float64
+	}{
+		// traced inside delta, replayed outside of it
+		{"huber", huberInputs, func(x []*micrograd.Value) *micrograd.Value {
+			return Huber(x, []float64{0}, 1)
+		}, []float64{5}},
+		// the shift must follow the logits, not stay at the traced maximum
+		{"softmax ce", ceInputs, func(x []*micrograd.Value) *micrograd.Value {
+			return SoftmaxCrossEntropy([][]*micrograd.Value{x}, []int{0})
+		}, []float64{800, 0}},
+	}
+	for _, tt := range tests {
+		tape, err := micrograd.Compile(tt.loss(tt.inputs))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		for i, x := range tt.next {
+			tt.inputs[i].Data = x
+		}
+		fresh := values(tt.next...)
+		want := tt.loss(fresh)
+		if got := tape.Forward(); math.Abs(got-want.Data) > 1e-12 {
+			t.Errorf("%s: replayed loss %v, want %v", tt.name, got, want.Data)
+		}
+		tape.Backward()
+		want.Backward()
+		for i, x := range tt.inputs {
+			if math.Abs(x.Grad-fresh[i].Grad) > 1e-12 {
+				t.Errorf("%s: replayed grad %d %v, want %v", tt.name, i, x.Grad, fresh[i].Grad)
+			}
+		}
+	}
+}
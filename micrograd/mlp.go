@@ -4,18 +4,28 @@ import "fmt"
 
 type MLP struct {
 	layers []*Layer
+	eval   bool
 }
 
-func NewMLP(nin int, nouts []int) *MLP {
+// NewMLP builds a network with nin inputs and one layer for every entry of nouts.
+// By default the hidden layers use ReLU, the output layer is linear and the
+// weights are drawn from U(-1, 1) using the global math/rand source.
+// WithBatchNorm and WithDropout regularize the hidden layers. A new network is in
+// training mode, see SetTraining.
+func NewMLP(nin int, nouts []int, opts ...Option) *MLP {
+	cfg := newConfig(opts)
 	depth := len(nouts) + 1
 	sizes := make([]int, depth)
 	sizes[0] = nin
 	for i := range nouts {
 		sizes[i+1] = nouts[i]
 	}
-	mlp := &MLP{make([]*Layer, len(nouts))}
+	mlp := &MLP{layers: make([]*Layer, len(nouts))}
 	for i := range nouts {
-		mlp.layers[i] = NewLayer(sizes[i], sizes[i+1], i != len(nouts)-1)
+		mlp.layers[i] = newLayer(sizes[i], sizes[i+1], cfg.activation(i, len(nouts)), cfg)
+		if i < len(nouts)-1 {
+			mlp.layers[i].regularize(cfg)
+		}
 	}
 	return mlp
 }
@@ -27,6 +37,73 @@ func (l *MLP) Forward(x []*Value) []*Value {
 	return x
 }
 
+// ForwardBatch runs a batch of examples through the network, which is how its
+// BatchNorm layers are trained. Without batch norm it is the same as calling
+// Forward on every example.
+func (l *MLP) ForwardBatch(xs [][]*Value) [][]*Value {
+	for _, layer := range l.layers {
+		xs = layer.ForwardBatch(xs)
+	}
+	return xs
+}
+
+// SetTraining switches the network between training mode, where Dropout drops
+// inputs and BatchNorm uses the batch statistics, and evaluation mode
+func (l *MLP) SetTraining(training bool) {
+	l.eval = !training
+	for _, layer := range l.layers {
+		layer.SetTraining(training)
+	}
+}
+
+func (l *MLP) Training() bool {
+	return !l.eval
+}
+
+// Compilable tells if Forward builds the same graph every time for the same
+// inputs, so that it can be recorded in a Tape or run for several examples at
+// once on different goroutines. It does not in training mode
+// with dropout, which draws new masks, or batch norm, which updates its
+// running statistics.
+func (l *MLP) Compilable() bool {
+	if l.eval {
+		return true
+	}
+	for _, layer := range l.layers {
+		if layer.norm != nil || layer.dropout != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Clone makes an independent copy of the network with the same weights, batch
+// norm statistics and mode, e.g. for the target network of a reinforcement
+// learning agent. Dropout layers of the copy share the random source of the original.
+func (l *MLP) Clone() *MLP {
+	out := &MLP{layers: make([]*Layer, len(l.layers)), eval: l.eval}
+	for i, layer := range l.layers {
+		c := &Layer{neurons: make([]*Neuron, len(layer.neurons))}
+		for k, n := range layer.neurons {
+			w := valuesData(n.w)
+			c.neurons[k] = restoreNeuron(w, n.b.Data, n.act)
+		}
+		if b := layer.norm; b != nil {
+			c.norm = restoreBatchNorm(valuesData(b.Gamma), valuesData(b.Beta), b.RunningMean, b.RunningVar, b.Momentum, b.Eps)
+			c.norm.eval = b.eval
+		}
+		if d := layer.dropout; d != nil {
+			c.dropout = &Dropout{P: d.P, rng: d.rng, eval: d.eval}
+		}
+		out.layers[i] = c
+	}
+	params := l.Parameters()
+	for i, p := range out.Parameters() {
+		p.frozen = params[i].frozen
+	}
+	return out
+}
+
 func (l *MLP) Parameters() []*Value {
 	parameters := make([]*Value, 0)
 	for _, layer := range l.layers {
@@ -35,10 +112,25 @@ func (l *MLP) Parameters() []*Value {
 	return parameters
 }
 
-func (l *MLP) ZeroGrad() {
-	for _, p := range l.Parameters() {
-		p.Grad = 0.0
+// NamedParameters prefixes the names of the layers with "layers.<index>"
+func (l *MLP) NamedParameters() []NamedParameter {
+	parameters := make([]NamedParameter, 0)
+	for i, layer := range l.layers {
+		parameters = append(parameters, prefixed(fmt.Sprintf("layers.%d", i), layer.NamedParameters())...)
 	}
+	return parameters
+}
+
+func (l *MLP) namedBuffers() []namedBuffer {
+	var buffers []namedBuffer
+	for i, layer := range l.layers {
+		buffers = append(buffers, prefixedBuffers(fmt.Sprintf("layers.%d", i), layer.namedBuffers())...)
+	}
+	return buffers
+}
+
+func (l *MLP) ZeroGrad() {
+	zeroGrad(l.Parameters())
 }
 
 func (l *MLP) String() string {
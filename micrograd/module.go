@@ -0,0 +1,175 @@
+package micrograd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Module is a part of a model holding trainable parameters: a Neuron, a Layer,
+// a BatchNorm or a whole MLP. NamedParameters lists the same parameters as
+// Parameters, in the same order, each with a dotted path like "layers.1.neurons.3.w.0".
+type Module interface {
+	Parameters() []*Value
+	NamedParameters() []NamedParameter
+	ZeroGrad()
+}
+
+type NamedParameter struct {
+	Name  string
+	Value *Value
+}
+
+// namedBuffer is a value a module keeps that is not trained by gradients, like
+// the running statistics of a BatchNorm, but belongs in its state
+type namedBuffer struct {
+	name  string
+	value *float64
+}
+
+// bufferModule is a Module with buffers
+type bufferModule interface {
+	namedBuffers() []namedBuffer
+}
+
+var (
+	_ Module = (*Neuron)(nil)
+	_ Module = (*Layer)(nil)
+	_ Module = (*BatchNorm)(nil)
+	_ Module = (*MLP)(nil)
+)
+
+// prefixed puts prefix and a dot in front of every name
+func prefixed(prefix string, params []NamedParameter) []NamedParameter {
+	for i := range params {
+		params[i].Name = prefix + "." + params[i].Name
+	}
+	return params
+}
+
+func prefixedBuffers(prefix string, buffers []namedBuffer) []namedBuffer {
+	for i := range buffers {
+		buffers[i].name = prefix + "." + buffers[i].name
+	}
+	return buffers
+}
+
+func zeroGrad(params []*Value) {
+	for _, p := range params {
+		p.Grad = 0.0
+	}
+}
+
+// StateDict copies the parameters and buffers of m into a map from their names to their data
+func StateDict(m Module) map[string]float64 {
+	state := map[string]float64{}
+	for _, p := range m.NamedParameters() {
+		state[p.Name] = p.Value.Data
+	}
+	if b, ok := m.(bufferModule); ok {
+		for _, buf := range b.namedBuffers() {
+			state[buf.name] = *buf.value
+		}
+	}
+	return state
+}
+
+// LoadReport lists the names LoadStateDict could not match, sorted
+type LoadReport struct {
+	// Missing are the names of m that are not in the state
+	Missing []string
+	// Unexpected are the names of the state that m does not have
+	Unexpected []string
+}
+
+// LoadStateDict copies the values of state into the parameters and buffers of m
+// with the same name. When strict, every name must match on both sides and
+// nothing is loaded otherwise. Without strict the matching names are loaded and
+// the others are reported, so that e.g. the first layers of a network can be
+// loaded from a smaller one.
+func LoadStateDict(m Module, state map[string]float64, strict bool) (LoadReport, error) {
+	targets := map[string]*float64{}
+	for _, p := range m.NamedParameters() {
+		targets[p.Name] = &p.Value.Data
+	}
+	if b, ok := m.(bufferModule); ok {
+		for _, buf := range b.namedBuffers() {
+			targets[buf.name] = buf.value
+		}
+	}
+
+	var report LoadReport
+	for name := range targets {
+		if _, ok := state[name]; !ok {
+			report.Missing = append(report.Missing, name)
+		}
+	}
+	for name := range state {
+		if _, ok := targets[name]; !ok {
+			report.Unexpected = append(report.Unexpected, name)
+		}
+	}
+	sort.Strings(report.Missing)
+	sort.Strings(report.Unexpected)
+	if strict && (len(report.Missing) > 0 || len(report.Unexpected) > 0) {
+		return report, fmt.Errorf("state dict mismatch: %d missing and %d unexpected names", len(report.Missing), len(report.Unexpected))
+	}
+	for name, target := range targets {
+		if v, ok := state[name]; ok {
+			*target = v
+		}
+	}
+	return report, nil
+}
+
+// matches tells if name is prefix or lies under it, so "layers.1" matches
+// "layers.1.neurons.0.b" but not "layers.10.neurons.0.b"
+func matches(name string, prefixes []string) bool {
+	if len(prefixes) == 0 {
+		return true
+	}
+	for _, prefix := range prefixes {
+		if name == prefix || strings.HasPrefix(name, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// Freeze stops the optimizers from updating the parameters of m under any of
+// the given prefixes, or all of them without prefixes, and returns how many it froze
+func Freeze(m Module, prefixes ...string) int {
+	return setFrozen(m, true, prefixes)
+}
+
+// Unfreeze lets the optimizers update the parameters frozen by Freeze again
+func Unfreeze(m Module, prefixes ...string) int {
+	return setFrozen(m, false, prefixes)
+}
+
+func setFrozen(m Module, frozen bool, prefixes []string) int {
+	n := 0
+	for _, p := range m.NamedParameters() {
+		if matches(p.Name, prefixes) {
+			p.Value.frozen = frozen
+			n++
+		}
+	}
+	return n
+}
+
+// Frozen tells if the optimizers leave v as it is
+func (v *Value) Frozen() bool {
+	return v.frozen
+}
+
+// TrainableParameters lists the parameters of m that are not frozen
+func TrainableParameters(m Module) []*Value {
+	var out []*Value
+	for _, p := range m.Parameters() {
+		if !p.frozen {
+			out = append(out, p)
+		}
+	}
+	return out
+}
@@ -0,0 +1,122 @@
+package micrograd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNamedParametersMatchParameters(t *testing.T) {
+	for _, m := range []Module{
+		NewNeuron(3, ReLU),
+		NewLayer(3, 2, Tanh, WithBatchNorm()),
+		NewBatchNorm(4),
+		NewMLP(2, []int{4, 5, 1}, WithBatchNorm()),
+	} {
+		named := m.NamedParameters()
+		params := m.Parameters()
+		if len(named) != len(params) {
+			t.Fatalf("%v: %d named parameters for %d parameters", m, len(named), len(params))
+		}
+		seen := map[string]bool{}
+		for i := range params {
+			if named[i].Value != params[i] {
+				t.Errorf("%v: parameter %d is %s in NamedParameters", m, i, named[i].Name)
+			}
+			if seen[named[i].Name] {
+				t.Errorf("%v: duplicate name %s", m, named[i].Name)
+			}
+			seen[named[i].Name] = true
+		}
+	}
+
+	mlp := NewMLP(2, []int{4, 5, 1}, WithBatchNorm())
+	names := map[string]*Value{}
+	for _, p := range mlp.NamedParameters() {
+		names[p.Name] = p.Value
+	}
+	for name, want := range map[string]*Value{
+		"layers.1.neurons.3.w.0": mlp.layers[1].neurons[3].w[0],
+		"layers.2.neurons.0.b":   mlp.layers[2].neurons[0].b,
+		"layers.0.norm.gamma.2":  mlp.layers[0].norm.Gamma[2],
+		"layers.1.norm.beta.4":   mlp.layers[1].norm.Beta[4],
+	} {
+		if names[name] != want {
+			t.Errorf("%s is not the expected parameter", name)
+		}
+	}
+}
+
+func TestStateDictRoundTrip(t *testing.T) {
+	src := NewMLP(2, []int{3, 1}, WithSeed(1), WithBatchNorm())
+	src.layers[0].norm.RunningMean[1] = 0.25
+	dst := NewMLP(2, []int{3, 1}, WithSeed(2), WithBatchNorm())
+
+	state := StateDict(src)
+	if state["layers.0.norm.running_mean.1"] != 0.25 {
+		t.Errorf("running statistics missing from the state: %v", state)
+	}
+	report, err := LoadStateDict(dst, state, true)
+	if err != nil || len(report.Missing) > 0 || len(report.Unexpected) > 0 {
+		t.Fatalf("strict load: %v %+v", err, report)
+	}
+	if !reflect.DeepEqual(StateDict(dst), state) {
+		t.Error("loaded state differs")
+	}
+}
+
+func TestLoadStateDictPartial(t *testing.T) {
+	small := NewMLP(2, []int{3, 1}, WithSeed(1))
+	big := NewMLP(2, []int{3, 4, 1}, WithSeed(2))
+	before := StateDict(big)
+
+	if _, err := LoadStateDict(big, StateDict(small), true); err == nil {
+		t.Fatal("strict load of a different architecture should fail")
+	}
+	if !reflect.DeepEqual(StateDict(big), before) {
+		t.Fatal("failed strict load changed the module")
+	}
+
+	report, err := LoadStateDict(big, StateDict(small), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// every name of small exists in big: its output neuron lands on the first neuron of big's second layer
+	if len(report.Unexpected) != 0 {
+		t.Errorf("unexpected %v", report.Unexpected)
+	}
+	if len(report.Missing) != len(before)-len(StateDict(small)) {
+		t.Errorf("missing %d names, want %d", len(report.Missing), len(before)-len(StateDict(small)))
+	}
+	after := StateDict(big)
+	for name, v := range StateDict(small) {
+		if after[name] != v {
+			t.Errorf("%s was not loaded", name)
+		}
+	}
+	for _, name := range report.Missing {
+		if after[name] != before[name] {
+			t.Errorf("%s changed without being in the state", name)
+		}
+	}
+}
+
+func TestFreeze(t *testing.T) {
+	mlp := NewMLP(2, []int{3, 1})
+	if n := Freeze(mlp, "layers.0.neurons.1", "layers.1"); n != 3+4 {
+		t.Errorf("froze %d parameters, want 7", n)
+	}
+	for _, p := range mlp.NamedParameters() {
+		want := strings.HasPrefix(p.Name, "layers.0.neurons.1.") || strings.HasPrefix(p.Name, "layers.1.")
+		if p.Value.Frozen() != want {
+			t.Errorf("%s frozen %v, want %v", p.Name, p.Value.Frozen(), want)
+		}
+	}
+	if n := len(TrainableParameters(mlp)); n != 13-7 {
+		t.Errorf("%d trainable parameters, want 6", n)
+	}
+	Unfreeze(mlp)
+	if n := len(TrainableParameters(mlp)); n != 13 {
+		t.Errorf("%d trainable parameters after Unfreeze, want 13", n)
+	}
+}
@@ -1,25 +1,31 @@
 package micrograd
 
-import (
-	"fmt"
-	"math/rand"
-)
+import "fmt"
 
 type Neuron struct {
-	w      []*Value
-	b      *Value
-	nonlin bool
+	w   []*Value
+	b   *Value
+	act Activation
 }
 
-func NewNeuron(nin int, nonlin bool) *Neuron {
-	neuron := &Neuron{w: make([]*Value, nin), b: NewValue(0.0), nonlin: nonlin}
+func NewNeuron(nin int, act Activation, opts ...Option) *Neuron {
+	return newNeuron(nin, 1, act, newConfig(opts))
+}
+
+func newNeuron(nin, fanOut int, act Activation, cfg *config) *Neuron {
+	neuron := &Neuron{w: make([]*Value, nin), b: NewValue(0.0), act: act}
 	for i := range neuron.w {
-		neuron.w[i] = NewValue((rand.Float64() * 2) - 1)
+		neuron.w[i] = NewValue(cfg.init(cfg.rng, nin, fanOut))
 	}
 	return neuron
 }
 
 func (n *Neuron) Forward(x []*Value) *Value {
+	return n.act.Apply(n.linear(x))
+}
+
+// linear is the weighted sum of the inputs plus the bias, before the activation
+func (n *Neuron) linear(x []*Value) *Value {
 	// check if the input is the same size as the weights
 	if len(x) != len(n.w) {
 		panic("input size mismatch")
@@ -28,9 +34,6 @@ func (n *Neuron) Forward(x []*Value) *Value {
 	for i := range x {
 		sum = sum.Add(n.w[i].Mul(x[i]))
 	}
-	if n.nonlin {
-		return sum.Relu()
-	}
 	return sum
 }
 
@@ -42,12 +45,19 @@ func (n *Neuron) Parameters() []*Value {
 	return out
 }
 
-func (n *Neuron) ZeroGrad() {
-	for _, p := range n.Parameters() {
-		p.Grad = 0.0
+// NamedParameters names the bias "b" and the weights "w.0", "w.1", ...
+func (n *Neuron) NamedParameters() []NamedParameter {
+	out := []NamedParameter{{"b", n.b}}
+	for i := range n.w {
+		out = append(out, NamedParameter{fmt.Sprintf("w.%d", i), n.w[i]})
 	}
+	return out
+}
+
+func (n *Neuron) ZeroGrad() {
+	zeroGrad(n.Parameters())
 }
 
 func (n *Neuron) String() string {
-	return fmt.Sprintf("Neuron(w=%v, b=%v)", n.w, n.b)
+	return fmt.Sprintf("Neuron(w=%v, b=%v, act=%v)", n.w, n.b, n.act)
 }
@@ -0,0 +1,29 @@
+package micrograd
+
+// NoGradValue makes an input for an inference pass, such as an accuracy check
+// or plotting a decision boundary. Operations on it, and on every value computed
+// from it, only compute Data: the results are leaves without children or
+// backward closures, which saves most of the allocations of an evaluation pass.
+// Parameters combined with it receive no gradient.
+//
+// The mode travels with the values, so inference on one goroutine leaves the
+// graphs built by other goroutines, from ordinary values, untouched.
+func NoGradValue(data float64) *Value {
+	v := NewValue(data)
+	v.nograd = true
+	return v
+}
+
+// NoGradValues makes a NoGradValue for every element of data
+func NoGradValues(data []float64) []*Value {
+	values := make([]*Value, len(data))
+	for i, d := range data {
+		values[i] = NoGradValue(d)
+	}
+	return values
+}
+
+// IsNoGrad tells if v was computed without recording its graph, see NoGradValue
+func (v *Value) IsNoGrad() bool {
+	return v.nograd
+}
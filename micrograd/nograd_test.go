@@ -0,0 +1,70 @@
+package micrograd
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestNoGradValue(t *testing.T) {
+	w := NewValue(2.0)
+	b := NewValue(3.0)
+	x := NoGradValue(0.5)
+	c := w.Mul(x).Add(b).Tanh()
+	if want := NewValue(2.0).Mul(NewValue(0.5)).Add(NewValue(3.0)).Tanh().Data; c.Data != want {
+		t.Errorf("got %v, want %v", c.Data, want)
+	}
+	if !c.IsNoGrad() || len(c.prev) != 0 || c.op != "" {
+		t.Errorf("inference result recorded a graph: op %q with %d children", c.op, len(c.prev))
+	}
+	c.Backward()
+	if w.Grad != 0 || b.Grad != 0 {
+		t.Errorf("gradient flowed through an inference result: %v %v", w.Grad, b.Grad)
+	}
+	// values that do not depend on the input still record their graph
+	if d := w.Mul(b); d.IsNoGrad() || len(d.prev) != 2 {
+		t.Error("a product of parameters lost its graph")
+	}
+}
+
+func TestNoGradConcurrentTraining(t *testing.T) {
+	mlp := NewMLP(2, []int{8, 1}, WithSeed(1))
+	grads := func() []float64 {
+		mlp.ZeroGrad()
+		mlp.Forward([]*Value{NewValue(0.5), NewValue(-0.5)})[0].Backward()
+		out := []float64{}
+		for _, p := range mlp.Parameters() {
+			out = append(out, p.Grad)
+		}
+		return out
+	}
+	want := grads()
+
+	// inference on the same model meanwhile must not turn the training graph into leaves
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			mlp.Forward(NoGradValues([]float64{0.1, 0.2}))
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		for k, g := range grads() {
+			if g != want[k] {
+				t.Fatalf("run %d parameter %d: grad %v, want %v", i, k, g, want[k])
+			}
+		}
+	}
+	wg.Wait()
+}
+
+func TestNoGradAllocations(t *testing.T) {
+	mlp := NewMLP(2, []int{16, 16, 1}, WithSeed(1))
+	x := []*Value{NewValue(0.5), NewValue(-0.5)}
+	withGrad := testing.AllocsPerRun(10, func() { mlp.Forward(x) })
+	x = NoGradValues([]float64{0.5, -0.5})
+	withoutGrad := testing.AllocsPerRun(10, func() { mlp.Forward(x) })
+	if withoutGrad*2 > withGrad {
+		t.Errorf("inference forward made %v allocations, with gradients %v", withoutGrad, withGrad)
+	}
+}
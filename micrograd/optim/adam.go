@@ -0,0 +1,78 @@
+package optim
+
+import (
+	"math"
+	"vdanciu_lang_model/micrograd"
+)
+
+// AdamConfig configures Adam and AdamW. Every field is used as given, start
+// from DefaultAdamConfig for the usual betas and eps.
+type AdamConfig struct {
+	LR          float64
+	Beta1       float64
+	Beta2       float64
+	Eps         float64
+	WeightDecay float64
+	// Decoupled applies WeightDecay directly to the weights (AdamW) instead of
+	// adding it to the gradient
+	Decoupled bool
+}
+
+// DefaultAdamConfig is the usual Adam setup: Beta1 0.9, Beta2 0.999, Eps 1e-8
+// and no weight decay
+func DefaultAdamConfig(lr float64) AdamConfig {
+	return AdamConfig{LR: lr, Beta1: 0.9, Beta2: 0.999, Eps: 1e-8}
+}
+
+type Adam struct {
+	params
+	cfg  AdamConfig
+	m    []float64
+	v    []float64
+	step int
+}
+
+func NewAdam(parameters []*micrograd.Value, cfg AdamConfig) *Adam {
+	return &Adam{
+		params: parameters,
+		cfg:    cfg,
+		m:      make([]float64, len(parameters)),
+		v:      make([]float64, len(parameters)),
+	}
+}
+
+// NewAdamW is Adam with decoupled weight decay
+func NewAdamW(parameters []*micrograd.Value, cfg AdamConfig) *Adam {
+	cfg.Decoupled = true
+	return NewAdam(parameters, cfg)
+}
+
+func (o *Adam) Step() {
+	o.step++
+	// bias corrections for the zero initialized moment estimates
+	c1 := 1 - math.Pow(o.cfg.Beta1, float64(o.step))
+	c2 := 1 - math.Pow(o.cfg.Beta2, float64(o.step))
+	for i, p := range o.params {
+		if p.Frozen() {
+			continue
+		}
+		var g float64
+		if o.cfg.Decoupled {
+			g = p.Grad
+			p.Data -= o.cfg.LR * o.cfg.WeightDecay * p.Data
+		} else {
+			g = o.params.grad(i, o.cfg.WeightDecay)
+		}
+		o.m[i] = o.cfg.Beta1*o.m[i] + (1-o.cfg.Beta1)*g
+		o.v[i] = o.cfg.Beta2*o.v[i] + (1-o.cfg.Beta2)*g*g
+		p.Data -= o.cfg.LR * (o.m[i] / c1) / (math.Sqrt(o.v[i]/c2) + o.cfg.Eps)
+	}
+}
+
+func (o *Adam) LR() float64 {
+	return o.cfg.LR
+}
+
+func (o *Adam) SetLR(lr float64) {
+	o.cfg.LR = lr
+}
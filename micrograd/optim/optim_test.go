@@ -0,0 +1,114 @@
+package optim
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"vdanciu_lang_model/micrograd"
+)
+
+// minimize runs steps of opt on f(x, y) = (x - 3)^2 + 10 * (y + 1)^2
+func minimize(opt Optimizer, x, y *micrograd.Value, steps int) {
+	for i := 0; i < steps; i++ {
+		opt.ZeroGrad()
+		dx := x.Sub(micrograd.NewValue(3))
+		dy := y.Add(micrograd.NewValue(1))
+		loss := dx.Pow(2).Add(dy.Pow(2).Mul(micrograd.NewValue(10)))
+		loss.Backward()
+		opt.Step()
+	}
+}
+
+func TestOptimizersConverge(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func([]*micrograd.Value) Optimizer
+	}{
+		{"sgd", func(p []*micrograd.Value) Optimizer { return NewSGD(p, SGDConfig{LR: 0.02}) }},
+		{"momentum", func(p []*micrograd.Value) Optimizer { return NewSGD(p, SGDConfig{LR: 0.02, Momentum: 0.9}) }},
+		{"nesterov", func(p []*micrograd.Value) Optimizer {
+			return NewSGD(p, SGDConfig{LR: 0.02, Momentum: 0.9, Nesterov: true})
+		}},
+		{"adam", func(p []*micrograd.Value) Optimizer { return NewAdam(p, DefaultAdamConfig(0.1)) }},
+		{"rmsprop", func(p []*micrograd.Value) Optimizer { return NewRMSProp(p, DefaultRMSPropConfig(0.01)) }},
+		{"rmsprop momentum", func(p []*micrograd.Value) Optimizer {
+			cfg := DefaultRMSPropConfig(0.01)
+			cfg.Momentum = 0.5
+			return NewRMSProp(p, cfg)
+		}},
+	}
+	for _, tt := range tests {
+		x, y := micrograd.NewValue(0), micrograd.NewValue(0)
+		minimize(tt.build([]*micrograd.Value{x, y}), x, y, 500)
+		if math.Abs(x.Data-3) > 1e-2 || math.Abs(y.Data+1) > 1e-2 {
+			t.Errorf("%s: got (%v, %v), want (3, -1)", tt.name, x.Data, y.Data)
+		}
+	}
+}
+
+func TestAdamFirstStep(t *testing.T) {
+	// with bias correction the first Adam step moves every parameter by ~LR
+	p := micrograd.NewValue(1.0)
+	p.Grad = 0.25
+	NewAdam([]*micrograd.Value{p}, DefaultAdamConfig(0.1)).Step()
+	if math.Abs(p.Data-0.9) > 1e-6 {
+		t.Errorf("got %v, want 0.9", p.Data)
+	}
+}
+
+func TestExplicitZeroConfig(t *testing.T) {
+	// Alpha 0 keeps only the last squared gradient, so the step is LR; the
+	// default 0.99 would make it 10 times larger
+	p := micrograd.NewValue(1.0)
+	p.Grad = 0.25
+	cfg := DefaultRMSPropConfig(0.1)
+	cfg.Alpha = 0
+	NewRMSProp([]*micrograd.Value{p}, cfg).Step()
+	if math.Abs(p.Data-0.9) > 1e-6 {
+		t.Errorf("got %v, want 0.9", p.Data)
+	}
+}
+
+func TestAdamWDecouplesWeightDecay(t *testing.T) {
+	// without a gradient only the decoupled decay moves the weight
+	p := micrograd.NewValue(2.0)
+	cfg := DefaultAdamConfig(0.1)
+	cfg.WeightDecay = 0.5
+	NewAdamW([]*micrograd.Value{p}, cfg).Step()
+	if math.Abs(p.Data-1.9) > 1e-9 {
+		t.Errorf("got %v, want 1.9", p.Data)
+	}
+}
+
+func TestZeroGrad(t *testing.T) {
+	p := micrograd.NewValue(1.0)
+	p.Grad = 3
+	NewSGD([]*micrograd.Value{p}, SGDConfig{LR: 0.1}).ZeroGrad()
+	if p.Grad != 0 {
+		t.Errorf("got grad %v, want 0", p.Grad)
+	}
+}
+
+func TestFrozenParametersStay(t *testing.T) {
+	mlp := micrograd.NewMLP(2, []int{3, 1}, micrograd.WithSeed(1))
+	if n := micrograd.Freeze(mlp, "layers.0"); n != 9 {
+		t.Fatalf("froze %d parameters, want 9", n)
+	}
+	before := micrograd.StateDict(mlp)
+	for _, opt := range []Optimizer{
+		NewSGD(mlp.Parameters(), SGDConfig{LR: 0.1, WeightDecay: 0.1}),
+		NewAdam(mlp.Parameters(), DefaultAdamConfig(0.1)),
+		NewRMSProp(mlp.Parameters(), DefaultRMSPropConfig(0.1)),
+	} {
+		opt.ZeroGrad()
+		mlp.Forward([]*micrograd.Value{micrograd.NewValue(1), micrograd.NewValue(-2)})[0].Backward()
+		opt.Step()
+	}
+	after := micrograd.StateDict(mlp)
+	for name, want := range before {
+		changed := after[name] != want
+		if frozen := strings.HasPrefix(name, "layers.0."); changed == frozen {
+			t.Errorf("%s: changed %v, frozen %v", name, changed, frozen)
+		}
+	}
+}
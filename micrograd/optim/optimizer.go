@@ -0,0 +1,32 @@
+// Package optim implements gradient based optimizers for micrograd parameters
+package optim
+
+import "vdanciu_lang_model/micrograd"
+
+// Optimizer updates a fixed set of parameters from their gradients.
+// A training step is: ZeroGrad, forward, Backward on the loss, then Step.
+// Step leaves frozen parameters (see micrograd.Freeze) as they are.
+type Optimizer interface {
+	// Step updates every parameter from its current Grad
+	Step()
+	// ZeroGrad resets the Grad of every parameter
+	ZeroGrad()
+	// LR returns the current learning rate
+	LR() float64
+	// SetLR changes the learning rate used by the next Step
+	SetLR(lr float64)
+}
+
+// params holds the parameters shared by every optimizer in this package
+type params []*micrograd.Value
+
+func (p params) ZeroGrad() {
+	for _, v := range p {
+		v.Grad = 0.0
+	}
+}
+
+// grad returns the gradient of the i-th parameter with L2 weight decay folded in
+func (p params) grad(i int, weightDecay float64) float64 {
+	return p[i].Grad + weightDecay*p[i].Data
+}
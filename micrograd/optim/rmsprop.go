@@ -0,0 +1,63 @@
+package optim
+
+import (
+	"math"
+	"vdanciu_lang_model/micrograd"
+)
+
+// RMSPropConfig configures RMSProp. Every field is used as given, start from
+// DefaultRMSPropConfig for the usual Alpha and Eps.
+type RMSPropConfig struct {
+	LR float64
+	// Alpha is the decay rate of the running average of squared gradients
+	Alpha       float64
+	Eps         float64
+	Momentum    float64
+	WeightDecay float64
+}
+
+// DefaultRMSPropConfig is the usual RMSProp setup: Alpha 0.99, Eps 1e-8, no
+// momentum and no weight decay
+func DefaultRMSPropConfig(lr float64) RMSPropConfig {
+	return RMSPropConfig{LR: lr, Alpha: 0.99, Eps: 1e-8}
+}
+
+type RMSProp struct {
+	params
+	cfg      RMSPropConfig
+	square   []float64
+	velocity []float64
+}
+
+func NewRMSProp(parameters []*micrograd.Value, cfg RMSPropConfig) *RMSProp {
+	return &RMSProp{
+		params:   parameters,
+		cfg:      cfg,
+		square:   make([]float64, len(parameters)),
+		velocity: make([]float64, len(parameters)),
+	}
+}
+
+func (o *RMSProp) Step() {
+	for i, p := range o.params {
+		if p.Frozen() {
+			continue
+		}
+		g := o.params.grad(i, o.cfg.WeightDecay)
+		o.square[i] = o.cfg.Alpha*o.square[i] + (1-o.cfg.Alpha)*g*g
+		update := g / (math.Sqrt(o.square[i]) + o.cfg.Eps)
+		if o.cfg.Momentum != 0 {
+			o.velocity[i] = o.cfg.Momentum*o.velocity[i] + update
+			update = o.velocity[i]
+		}
+		p.Data -= o.cfg.LR * update
+	}
+}
+
+func (o *RMSProp) LR() float64 {
+	return o.cfg.LR
+}
+
+func (o *RMSProp) SetLR(lr float64) {
+	o.cfg.LR = lr
+}
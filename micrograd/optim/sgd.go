@@ -0,0 +1,49 @@
+package optim
+
+import "vdanciu_lang_model/micrograd"
+
+type SGDConfig struct {
+	LR       float64
+	Momentum float64
+	// Nesterov applies the momentum lookahead; it has no effect without Momentum
+	Nesterov bool
+	// WeightDecay adds an L2 penalty of WeightDecay * p to every gradient
+	WeightDecay float64
+}
+
+// SGD is stochastic gradient descent with optional (Nesterov) momentum
+type SGD struct {
+	params
+	cfg      SGDConfig
+	velocity []float64
+}
+
+func NewSGD(parameters []*micrograd.Value, cfg SGDConfig) *SGD {
+	return &SGD{params: parameters, cfg: cfg, velocity: make([]float64, len(parameters))}
+}
+
+func (o *SGD) Step() {
+	for i, p := range o.params {
+		if p.Frozen() {
+			continue
+		}
+		g := o.params.grad(i, o.cfg.WeightDecay)
+		if o.cfg.Momentum != 0 {
+			o.velocity[i] = o.cfg.Momentum*o.velocity[i] + g
+			if o.cfg.Nesterov {
+				g += o.cfg.Momentum * o.velocity[i]
+			} else {
+				g = o.velocity[i]
+			}
+		}
+		p.Data -= o.cfg.LR * g
+	}
+}
+
+func (o *SGD) LR() float64 {
+	return o.cfg.LR
+}
+
+func (o *SGD) SetLR(lr float64) {
+	o.cfg.LR = lr
+}
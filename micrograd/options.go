@@ -0,0 +1,102 @@
+package micrograd
+
+import "math/rand"
+
+// Option configures the networks built by NewMLP, NewLayer and NewNeuron
+type Option func(*config)
+
+type config struct {
+	// activations holds one activation per layer, it overrides hidden and output
+	activations []Activation
+	hidden      Activation
+	output      Activation
+	rng         *rand.Rand
+	init        Initializer
+	dropout     float64
+	batchNorm   bool
+}
+
+func defaultConfig() *config {
+	// ReLU on the hidden layers and a linear output, like the original micrograd
+	// and weights drawn from U(-1, 1) with the global math/rand source
+	return &config{hidden: ReLU, output: Identity, rng: rand.New(globalSource{}), init: Uniform}
+}
+
+func newConfig(opts []Option) *config {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// activation returns the activation of layer i out of n
+func (c *config) activation(i, n int) Activation {
+	if c.activations != nil {
+		if len(c.activations) != n {
+			panic("activation count mismatch")
+		}
+		return c.activations[i]
+	}
+	if i == n-1 {
+		return c.output
+	}
+	return c.hidden
+}
+
+// WithActivations sets the activation of every layer, one per layer
+func WithActivations(acts ...Activation) Option {
+	return func(c *config) {
+		c.activations = acts
+	}
+}
+
+// WithHiddenActivation sets the activation of every layer but the last
+func WithHiddenActivation(a Activation) Option {
+	return func(c *config) {
+		c.hidden = a
+	}
+}
+
+// WithOutputActivation sets the activation of the last layer
+func WithOutputActivation(a Activation) Option {
+	return func(c *config) {
+		c.output = a
+	}
+}
+
+// WithRand draws the initial weights from r instead of the global math/rand source
+func WithRand(r *rand.Rand) Option {
+	return func(c *config) {
+		c.rng = r
+	}
+}
+
+// WithSeed draws the initial weights from a new source seeded with seed,
+// so the same seed always builds the same network
+func WithSeed(seed int64) Option {
+	return WithRand(rand.New(rand.NewSource(seed)))
+}
+
+// WithInit sets how the initial weights are drawn, biases always start at 0
+func WithInit(init Initializer) Option {
+	return func(c *config) {
+		c.init = init
+	}
+}
+
+// WithDropout adds Dropout with probability p after the activation of every
+// hidden layer (of the layer itself for NewLayer)
+func WithDropout(p float64) Option {
+	return func(c *config) {
+		c.dropout = p
+	}
+}
+
+// WithBatchNorm adds a BatchNorm between the weighted sums and the activation of
+// every hidden layer (of the layer itself for NewLayer)
+func WithBatchNorm() Option {
+	return func(c *config) {
+		c.batchNorm = true
+	}
+}
@@ -0,0 +1,234 @@
+// Package plotting draws what 2D classifiers have learned with gonum/plot
+package plotting
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+	"strings"
+	"vdanciu_lang_model/micrograd"
+	"vdanciu_lang_model/micrograd/train"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/plotutil"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+type BoundaryConfig struct {
+	Title string
+	// Resolution is the number of grid points along each axis (defaults to 100)
+	Resolution int
+	// Margin pads the range of the inputs on every side (defaults to 0.5)
+	Margin float64
+	// Contour draws the line where the score of a single output model is 0
+	Contour bool
+	// Width and Height of the saved image (default to 4 inches)
+	Width, Height vg.Length
+}
+
+// DecisionBoundary evaluates model on a grid covering the inputs of data and
+// draws the predicted class of every grid cell behind a scatter of the labelled
+// points. A model with one output predicts the larger label when its output is
+// positive. A model with several outputs must have one output per label: its
+// largest output k predicts the k-th smallest label, which for the class
+// indices of train.ClassLoss is label k.
+func DecisionBoundary(model train.Model, data train.Dataset, cfg BoundaryConfig) (*plot.Plot, error) {
+	if data.Len() == 0 {
+		return nil, errors.New("no data to plot")
+	}
+	if len(data.X[0]) != 2 {
+		return nil, fmt.Errorf("decision boundaries need 2D inputs, got %d", len(data.X[0]))
+	}
+	classes := labels(data.Y)
+	grid, err := newGrid(model, data, cfg, classes)
+	if err != nil {
+		return nil, err
+	}
+
+	p := plot.New()
+	p.Title.Text = cfg.Title
+	p.X.Label.Text = "X"
+	p.Y.Label.Text = "Y"
+
+	regions := plotter.NewHeatMap(grid, regionPalette(len(classes)))
+	regions.Min = 0
+	regions.Max = float64(max(len(classes)-1, 1))
+	p.Add(regions)
+
+	if cfg.Contour && grid.scoreMin < 0 && grid.scoreMax > 0 {
+		contour := plotter.NewContour(scoreGrid{grid}, []float64{0}, nil)
+		contour.Min, contour.Max = grid.scoreMin, grid.scoreMax
+		contour.LineStyles[0].Width = vg.Points(1.5)
+		contour.LineStyles[0].Color = color.Black
+		p.Add(contour)
+	}
+
+	for i, class := range classes {
+		pts := plotter.XYs{}
+		for k := range data.X {
+			if data.Y[k] == class {
+				pts = append(pts, plotter.XY{X: data.X[k][0], Y: data.X[k][1]})
+			}
+		}
+		scatter, err := plotter.NewScatter(pts)
+		if err != nil {
+			return nil, err
+		}
+		scatter.GlyphStyle.Color = plotutil.Color(i)
+		scatter.GlyphStyle.Shape = draw.CircleGlyph{}
+		p.Add(scatter)
+		p.Legend.Add(fmt.Sprintf("%v", class), scatter)
+	}
+
+	p.X.Min, p.X.Max = grid.X(0), grid.X(grid.n-1)
+	p.Y.Min, p.Y.Max = grid.Y(0), grid.Y(grid.n-1)
+	return p, nil
+}
+
+// SaveDecisionBoundary draws the decision boundary and saves it to filename,
+// the format (png, svg, pdf, ...) follows the file extension
+func SaveDecisionBoundary(model train.Model, data train.Dataset, cfg BoundaryConfig, filename string) error {
+	p, err := DecisionBoundary(model, data, cfg)
+	if err != nil {
+		return err
+	}
+	width, height := cfg.Width, cfg.Height
+	if width == 0 {
+		width = 4 * vg.Inch
+	}
+	if height == 0 {
+		height = 4 * vg.Inch
+	}
+	return p.Save(width, height, filename)
+}
+
+// Frames is a train.Callback that saves the decision boundary every Every epochs
+// (every epoch if Every is 0), so the training progress can be watched frame by frame.
+// Pattern is the file name with a %d verb for the epoch, e.g. "frames/epoch%03d.png".
+type Frames struct {
+	Data    train.Dataset
+	Pattern string
+	Every   int
+	Config  BoundaryConfig
+	// Err holds the last error returned while saving a frame
+	Err error
+}
+
+func (f *Frames) OnEpochEnd(t *train.Trainer, epoch int, logs train.Logs) {
+	if f.Every > 1 && epoch%f.Every != 0 {
+		return
+	}
+	cfg := f.Config
+	cfg.Title = strings.TrimSpace(fmt.Sprintf("%s epoch %d loss %.4f", cfg.Title, epoch, logs["loss"]))
+	if err := SaveDecisionBoundary(t.Model, f.Data, cfg, fmt.Sprintf(f.Pattern, epoch)); err != nil {
+		f.Err = err
+	}
+}
+
+// grid holds the predictions of the model over a square grid of inputs
+type grid struct {
+	n                  int
+	x0, y0, dx, dy     float64
+	class              []float64
+	score              []float64
+	scoreMin, scoreMax float64
+}
+
+func newGrid(model train.Model, data train.Dataset, cfg BoundaryConfig, classes []float64) (*grid, error) {
+	n := cfg.Resolution
+	if n <= 1 {
+		n = 100
+	}
+	margin := cfg.Margin
+	if margin == 0 {
+		margin = 0.5
+	}
+	xmin, xmax := math.Inf(1), math.Inf(-1)
+	ymin, ymax := math.Inf(1), math.Inf(-1)
+	for _, row := range data.X {
+		xmin, xmax = math.Min(xmin, row[0]), math.Max(xmax, row[0])
+		ymin, ymax = math.Min(ymin, row[1]), math.Max(ymax, row[1])
+	}
+	g := &grid{
+		n:        n,
+		x0:       xmin - margin,
+		y0:       ymin - margin,
+		dx:       (xmax - xmin + 2*margin) / float64(n-1),
+		dy:       (ymax - ymin + 2*margin) / float64(n-1),
+		class:    make([]float64, n*n),
+		score:    make([]float64, n*n),
+		scoreMin: math.Inf(1),
+		scoreMax: math.Inf(-1),
+	}
+	// only the predictions are needed, skip recording the graph and predict in evaluation mode
+	if m, ok := model.(train.ModeModel); ok {
+		defer m.SetTraining(m.Training())
+		m.SetTraining(false)
+	}
+	for r := 0; r < n; r++ {
+		for c := 0; c < n; c++ {
+			out := model.Forward([]*micrograd.Value{micrograd.NoGradValue(g.X(c)), micrograd.NoGradValue(g.Y(r))})
+			i := r*n + c
+			if len(out) == 1 {
+				g.score[i] = out[0].Data
+				if out[0].Data > 0 {
+					g.class[i] = float64(len(classes) - 1)
+				}
+			} else if len(out) != len(classes) {
+				return nil, fmt.Errorf("model has %d outputs for %d labels", len(out), len(classes))
+			} else {
+				// the index of a label in the sorted classes is its color
+				g.class[i] = float64(train.Argmax(out))
+			}
+			g.scoreMin = math.Min(g.scoreMin, g.score[i])
+			g.scoreMax = math.Max(g.scoreMax, g.score[i])
+		}
+	}
+	return g, nil
+}
+
+func (g *grid) Dims() (c, r int)   { return g.n, g.n }
+func (g *grid) Z(c, r int) float64 { return g.class[r*g.n+c] }
+func (g *grid) X(c int) float64    { return g.x0 + float64(c)*g.dx }
+func (g *grid) Y(r int) float64    { return g.y0 + float64(r)*g.dy }
+
+// scoreGrid exposes the raw single output scores of a grid for contouring
+type scoreGrid struct {
+	*grid
+}
+
+func (g scoreGrid) Z(c, r int) float64 { return g.score[r*g.n+c] }
+
+// regionPalette returns a light version of the scatter color of every class
+type regionPalette int
+
+func (p regionPalette) Colors() []color.Color {
+	colors := make([]color.Color, max(int(p), 1))
+	for i := range colors {
+		r, g, b, _ := plotutil.Color(i).RGBA()
+		// blend with white so the points stay visible on top
+		colors[i] = color.NRGBA{R: lighten(r), G: lighten(g), B: lighten(b), A: 0xff}
+	}
+	return colors
+}
+
+func lighten(c uint32) uint8 {
+	return uint8((c>>8)/3 + 0xff*2/3)
+}
+
+func labels(y []float64) []float64 {
+	seen := map[float64]bool{}
+	out := []float64{}
+	for _, v := range y {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	sort.Float64s(out)
+	return out
+}
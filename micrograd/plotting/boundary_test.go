@@ -0,0 +1,104 @@
+package plotting
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"vdanciu_lang_model/micrograd"
+	"vdanciu_lang_model/micrograd/losses"
+	"vdanciu_lang_model/micrograd/optim"
+	"vdanciu_lang_model/micrograd/train"
+)
+
+// diagonal scores points by x - y, so the boundary is the line x = y
+type diagonal struct{}
+
+func (diagonal) Forward(x []*micrograd.Value) []*micrograd.Value {
+	return []*micrograd.Value{x[0].Sub(x[1])}
+}
+
+func (diagonal) Parameters() []*micrograd.Value {
+	return nil
+}
+
+var points = train.Dataset{
+	X: [][]float64{{1, 0}, {2, 1}, {0, 1}, {-1, 2}},
+	Y: []float64{1, 1, -1, -1},
+}
+
+func TestGridPredictions(t *testing.T) {
+	g, err := newGrid(diagonal{}, points, BoundaryConfig{Resolution: 11}, labels(points.Y))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for r := 0; r < g.n; r++ {
+		for c := 0; c < g.n; c++ {
+			want := 0.0
+			if g.X(c) > g.Y(r) {
+				want = 1.0
+			}
+			if g.Z(c, r) != want {
+				t.Fatalf("cell (%v, %v): got class %v, want %v", g.X(c), g.Y(r), g.Z(c, r), want)
+			}
+		}
+	}
+}
+
+// oneHot has an output per label of threeClasses, the largest where x + y is largest
+type oneHot struct{}
+
+func (oneHot) Forward(x []*micrograd.Value) []*micrograd.Value {
+	return []*micrograd.Value{x[0].Neg(), micrograd.NewValue(0), x[0]}
+}
+
+func (oneHot) Parameters() []*micrograd.Value {
+	return nil
+}
+
+func TestGridMultiOutputLabels(t *testing.T) {
+	// 1-based labels: output k predicts the k-th smallest label
+	data := train.Dataset{X: [][]float64{{-1, 0}, {0, 0}, {1, 0}}, Y: []float64{3, 1, 2}}
+	g, err := newGrid(oneHot{}, data, BoundaryConfig{Resolution: 5, Margin: 0.1}, labels(data.Y))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if left, right := g.Z(0, 0), g.Z(g.n-1, 0); left != 0 || right != 2 {
+		t.Errorf("got classes %v and %v at the edges, want 0 and 2", left, right)
+	}
+	// a label missing from the data leaves an output without a label
+	data = train.Dataset{X: data.X[:2], Y: data.Y[:2]}
+	if _, err := DecisionBoundary(oneHot{}, data, BoundaryConfig{}); err == nil {
+		t.Error("expected an error for 3 outputs and 2 labels")
+	}
+}
+
+func TestSaveDecisionBoundary(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"boundary.png", "boundary.svg"} {
+		filename := filepath.Join(dir, name)
+		cfg := BoundaryConfig{Title: "diagonal", Resolution: 20, Contour: true}
+		if err := SaveDecisionBoundary(diagonal{}, points, cfg, filename); err != nil {
+			t.Fatal(err)
+		}
+		if info, err := os.Stat(filename); err != nil || info.Size() == 0 {
+			t.Errorf("%s was not written: %v", name, err)
+		}
+	}
+}
+
+func TestFrames(t *testing.T) {
+	dir := t.TempDir()
+	mlp := micrograd.NewMLP(2, []int{4, 1}, micrograd.WithSeed(1))
+	opt := optim.NewSGD(mlp.Parameters(), optim.SGDConfig{LR: 0.1})
+	trainer := train.New(mlp, train.ScalarLoss(losses.Hinge), opt, train.Config{Epochs: 4})
+	frames := &Frames{Data: points, Pattern: filepath.Join(dir, "epoch%d.png"), Every: 2, Config: BoundaryConfig{Resolution: 10}}
+	trainer.Callbacks = append(trainer.Callbacks, frames)
+	trainer.Fit(points)
+	if frames.Err != nil {
+		t.Fatal(frames.Err)
+	}
+	written, _ := filepath.Glob(filepath.Join(dir, "epoch*.png"))
+	if len(written) != 2 {
+		t.Errorf("got frames %v, want epoch0.png and epoch2.png", written)
+	}
+}
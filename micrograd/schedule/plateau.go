@@ -0,0 +1,53 @@
+package schedule
+
+// ReduceOnPlateau lowers the learning rate when a monitored metric (usually the
+// validation loss) stops improving. Unlike a Schedule it is driven by metric
+// values instead of step counts.
+type ReduceOnPlateau struct {
+	// Factor multiplies the learning rate on every reduction (defaults to 0.1)
+	Factor float64
+	// Patience is the number of observations without improvement that are tolerated
+	Patience int
+	// Threshold is the minimum change that counts as an improvement
+	Threshold float64
+	// Min is the lowest learning rate the scheduler will go to
+	Min float64
+	// Maximize is set when a larger metric is better (e.g. accuracy)
+	Maximize bool
+
+	lr       float64
+	best     float64
+	bad      int
+	observed bool
+}
+
+func NewReduceOnPlateau(lr float64, patience int) *ReduceOnPlateau {
+	return &ReduceOnPlateau{Factor: 0.1, Patience: patience, lr: lr}
+}
+
+// Observe records a new metric value and returns the learning rate to use next
+func (s *ReduceOnPlateau) Observe(metric float64) float64 {
+	if !s.observed || s.improved(metric) {
+		s.best = metric
+		s.bad = 0
+		s.observed = true
+		return s.lr
+	}
+	s.bad++
+	if s.bad > s.Patience {
+		s.lr = max(s.lr*orDefault(s.Factor, 0.1), s.Min)
+		s.bad = 0
+	}
+	return s.lr
+}
+
+func (s *ReduceOnPlateau) LR() float64 {
+	return s.lr
+}
+
+func (s *ReduceOnPlateau) improved(metric float64) bool {
+	if s.Maximize {
+		return metric > s.best+s.Threshold
+	}
+	return metric < s.best-s.Threshold
+}
@@ -0,0 +1,121 @@
+// Package schedule implements learning rate schedules. Schedules only compute
+// learning rates, so they can drive an optim.Optimizer through SetLR as well as
+// the hand written update of the migete training loop.
+package schedule
+
+import "math"
+
+// Schedule maps a step (or epoch) count, starting at 0, to a learning rate
+type Schedule interface {
+	LR(step int) float64
+}
+
+// Func adapts an ordinary function to a Schedule
+type Func func(step int) float64
+
+func (f Func) LR(step int) float64 {
+	return f(step)
+}
+
+type Constant float64
+
+func (c Constant) LR(step int) float64 {
+	return float64(c)
+}
+
+// Linear interpolates from Start to End over Steps steps and stays at End afterwards
+type Linear struct {
+	Start, End float64
+	Steps      int
+}
+
+func (s Linear) LR(step int) float64 {
+	if step >= s.Steps {
+		return s.End
+	}
+	return s.Start + (s.End-s.Start)*float64(step)/float64(s.Steps)
+}
+
+// StepDecay multiplies the base learning rate by Gamma every StepSize steps.
+// A zero StepSize defaults to 1, decaying every step.
+type StepDecay struct {
+	Base     float64
+	Gamma    float64
+	StepSize int
+}
+
+func (s StepDecay) LR(step int) float64 {
+	stepSize := s.StepSize
+	if stepSize == 0 {
+		stepSize = 1
+	}
+	return s.Base * math.Pow(s.Gamma, float64(step/stepSize))
+}
+
+// Exponential multiplies the base learning rate by Gamma every step
+type Exponential struct {
+	Base  float64
+	Gamma float64
+}
+
+func (s Exponential) LR(step int) float64 {
+	return s.Base * math.Pow(s.Gamma, float64(step))
+}
+
+// CosineAnnealing ramps up linearly from 0 to Base over Warmup steps, then
+// follows a half cosine from Base down to Min over the remaining Total-Warmup steps
+type CosineAnnealing struct {
+	Base   float64
+	Min    float64
+	Warmup int
+	Total  int
+}
+
+func (s CosineAnnealing) LR(step int) float64 {
+	if step < s.Warmup {
+		return s.Base * float64(step+1) / float64(s.Warmup)
+	}
+	if step >= s.Total {
+		return s.Min
+	}
+	progress := float64(step-s.Warmup) / float64(s.Total-s.Warmup)
+	return s.Min + (s.Base-s.Min)*(1+math.Cos(math.Pi*progress))/2
+}
+
+// OneCycle is the 1cycle policy: a cosine ramp from Max/DivFactor up to Max during
+// the first PctStart of Total steps, then a cosine ramp down to Max/FinalDivFactor.
+// Zero values for PctStart, DivFactor and FinalDivFactor default to 0.3, 25 and 1e4.
+type OneCycle struct {
+	Max            float64
+	Total          int
+	PctStart       float64
+	DivFactor      float64
+	FinalDivFactor float64
+}
+
+func (s OneCycle) LR(step int) float64 {
+	pctStart := orDefault(s.PctStart, 0.3)
+	initial := s.Max / orDefault(s.DivFactor, 25)
+	final := s.Max / orDefault(s.FinalDivFactor, 1e4)
+
+	up := int(pctStart * float64(s.Total))
+	if step < up {
+		return cosineRamp(initial, s.Max, float64(step)/float64(up))
+	}
+	if step >= s.Total {
+		return final
+	}
+	return cosineRamp(s.Max, final, float64(step-up)/float64(s.Total-up))
+}
+
+// cosineRamp goes from start to end as progress goes from 0 to 1
+func cosineRamp(start, end, progress float64) float64 {
+	return end + (start-end)*(1+math.Cos(math.Pi*progress))/2
+}
+
+func orDefault(v, def float64) float64 {
+	if v == 0 {
+		return def
+	}
+	return v
+}
@@ -0,0 +1,48 @@
+package schedule
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSchedules(t *testing.T) {
+	tests := []struct {
+		name string
+		s    Schedule
+		step int
+		want float64
+	}{
+		{"constant", Constant(0.5), 10, 0.5},
+		{"linear start", Linear{Start: 1, End: 0.1, Steps: 100}, 0, 1},
+		{"linear middle", Linear{Start: 1, End: 0.1, Steps: 100}, 50, 0.55},
+		{"linear end", Linear{Start: 1, End: 0.1, Steps: 100}, 150, 0.1},
+		{"step decay", StepDecay{Base: 1, Gamma: 0.5, StepSize: 10}, 25, 0.25},
+		{"step decay default size", StepDecay{Base: 1, Gamma: 0.5}, 2, 0.25},
+		{"exponential", Exponential{Base: 2, Gamma: 0.5}, 3, 0.25},
+		{"cosine warmup", CosineAnnealing{Base: 1, Warmup: 10, Total: 110}, 4, 0.5},
+		{"cosine peak", CosineAnnealing{Base: 1, Warmup: 10, Total: 110}, 10, 1},
+		{"cosine middle", CosineAnnealing{Base: 1, Min: 0.2, Warmup: 10, Total: 110}, 60, 0.6},
+		{"cosine end", CosineAnnealing{Base: 1, Min: 0.2, Warmup: 10, Total: 110}, 110, 0.2},
+		{"one cycle start", OneCycle{Max: 1, Total: 100}, 0, 0.04},
+		{"one cycle peak", OneCycle{Max: 1, Total: 100}, 30, 1},
+		{"one cycle end", OneCycle{Max: 1, Total: 100}, 100, 1e-4},
+		{"func", Func(func(step int) float64 { return 1 / float64(step+1) }), 3, 0.25},
+	}
+	for _, tt := range tests {
+		if got := tt.s.LR(tt.step); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("%s: LR(%d) = %v, want %v", tt.name, tt.step, got, tt.want)
+		}
+	}
+}
+
+func TestReduceOnPlateau(t *testing.T) {
+	s := NewReduceOnPlateau(1.0, 2)
+	s.Factor = 0.5
+	losses := []float64{1.0, 0.9, 0.95, 0.91, 0.92, 0.8, 0.85, 0.85, 0.85}
+	want := []float64{1.0, 1.0, 1.0, 1.0, 0.5, 0.5, 0.5, 0.5, 0.25}
+	for i, loss := range losses {
+		if got := s.Observe(loss); got != want[i] {
+			t.Errorf("observation %d: got lr %v, want %v", i, got, want[i])
+		}
+	}
+}
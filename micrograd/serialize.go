@@ -0,0 +1,360 @@
+package micrograd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// the JSON representation of an MLP: the architecture plus every weight and bias
+type mlpJSON struct {
+	Layers []layerJSON `json:"layers"`
+}
+
+type layerJSON struct {
+	Nin        int    `json:"nin"`
+	Nout       int    `json:"nout"`
+	Activation string `json:"activation"`
+	// Nonlin is only read from files saved before activations were configurable
+	Nonlin    bool           `json:"nonlin,omitempty"`
+	Neurons   []neuronJSON   `json:"neurons"`
+	BatchNorm *batchNormJSON `json:"batch_norm,omitempty"`
+	Dropout   float64        `json:"dropout,omitempty"`
+}
+
+type neuronJSON struct {
+	W []float64 `json:"w"`
+	B float64   `json:"b"`
+}
+
+type batchNormJSON struct {
+	Gamma       []float64 `json:"gamma"`
+	Beta        []float64 `json:"beta"`
+	RunningMean []float64 `json:"running_mean"`
+	RunningVar  []float64 `json:"running_var"`
+	Momentum    float64   `json:"momentum"`
+	Eps         float64   `json:"eps"`
+}
+
+func (l *MLP) MarshalJSON() ([]byte, error) {
+	out := mlpJSON{Layers: make([]layerJSON, len(l.layers))}
+	for i, layer := range l.layers {
+		lj := layerJSON{Nin: layer.nin(), Nout: len(layer.neurons), Activation: layer.activation().String()}
+		for _, n := range layer.neurons {
+			nj := neuronJSON{W: make([]float64, len(n.w)), B: n.b.Data}
+			for k, w := range n.w {
+				nj.W[k] = w.Data
+			}
+			lj.Neurons = append(lj.Neurons, nj)
+		}
+		if b := layer.norm; b != nil {
+			lj.BatchNorm = &batchNormJSON{
+				Gamma:       valuesData(b.Gamma),
+				Beta:        valuesData(b.Beta),
+				RunningMean: b.RunningMean,
+				RunningVar:  b.RunningVar,
+				Momentum:    b.Momentum,
+				Eps:         b.Eps,
+			}
+		}
+		if layer.dropout != nil {
+			lj.Dropout = layer.dropout.P
+		}
+		out.Layers[i] = lj
+	}
+	return json.Marshal(out)
+}
+
+func (l *MLP) UnmarshalJSON(data []byte) error {
+	var in mlpJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	layers := make([]*Layer, len(in.Layers))
+	for i, lj := range in.Layers {
+		if len(lj.Neurons) != lj.Nout {
+			return fmt.Errorf("layer %d: expected %d neurons, got %d", i, lj.Nout, len(lj.Neurons))
+		}
+		act, err := layerActivation(lj.Activation, lj.Nonlin)
+		if err != nil {
+			return fmt.Errorf("layer %d: %w", i, err)
+		}
+		layer := &Layer{neurons: make([]*Neuron, lj.Nout)}
+		for k, nj := range lj.Neurons {
+			if len(nj.W) != lj.Nin {
+				return fmt.Errorf("layer %d neuron %d: expected %d weights, got %d", i, k, lj.Nin, len(nj.W))
+			}
+			layer.neurons[k] = restoreNeuron(nj.W, nj.B, act)
+		}
+		if bj := lj.BatchNorm; bj != nil {
+			for _, stat := range [][]float64{bj.Gamma, bj.Beta, bj.RunningMean, bj.RunningVar} {
+				if len(stat) != lj.Nout {
+					return fmt.Errorf("layer %d: expected %d batch norm features, got %d", i, lj.Nout, len(stat))
+				}
+			}
+			layer.norm = restoreBatchNorm(bj.Gamma, bj.Beta, bj.RunningMean, bj.RunningVar, bj.Momentum, bj.Eps)
+		}
+		if lj.Dropout < 0 || lj.Dropout >= 1 {
+			return fmt.Errorf("layer %d: invalid dropout probability %v", i, lj.Dropout)
+		} else if lj.Dropout > 0 {
+			layer.dropout = NewDropout(lj.Dropout, nil)
+		}
+		layers[i] = layer
+	}
+	if err := checkLayerSizes(layers); err != nil {
+		return err
+	}
+	l.layers = layers
+	return nil
+}
+
+// binaryMagic starts every binary encoded MLP, followed by a format version byte.
+// Version 1 stored a nonlin flag per layer, version 2 stores the activation name
+// and version 3 adds the dropout and batch norm of every layer.
+var binaryMagic = []byte{'M', 'L', 'P'}
+
+const binaryVersion = 3
+
+// MarshalBinary encodes the MLP compactly: the magic header, the number of
+// layers, then for every layer its sizes, its activation name, its dropout
+// probability, a batch norm flag, the little endian float64 bias and weights of
+// each neuron and, with batch norm, its momentum, eps, gamma, beta, running mean
+// and running variance.
+func (l *MLP) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(binaryMagic)
+	buf.WriteByte(binaryVersion)
+	write := func(v any) {
+		// writes to a bytes.Buffer never fail
+		_ = binary.Write(&buf, binary.LittleEndian, v)
+	}
+	write(uint32(len(l.layers)))
+	for _, layer := range l.layers {
+		write(uint32(layer.nin()))
+		write(uint32(len(layer.neurons)))
+		name := layer.activation().String()
+		write(uint16(len(name)))
+		buf.WriteString(name)
+		dropout := 0.0
+		if layer.dropout != nil {
+			dropout = layer.dropout.P
+		}
+		write(dropout)
+		write(layer.norm != nil)
+		for _, n := range layer.neurons {
+			write(n.b.Data)
+			for _, w := range n.w {
+				write(w.Data)
+			}
+		}
+		if b := layer.norm; b != nil {
+			write(b.Momentum)
+			write(b.Eps)
+			write(valuesData(b.Gamma))
+			write(valuesData(b.Beta))
+			write(b.RunningMean)
+			write(b.RunningVar)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func (l *MLP) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	magic := make([]byte, len(binaryMagic)+1)
+	if _, err := io.ReadFull(r, magic); err != nil || !bytes.Equal(magic[:len(binaryMagic)], binaryMagic) {
+		return errors.New("not a binary encoded MLP")
+	}
+	version := magic[len(binaryMagic)]
+	if version < 1 || version > binaryVersion {
+		return fmt.Errorf("unsupported binary MLP version %d", version)
+	}
+	var err error
+	read := func(v any) {
+		if err == nil {
+			err = binary.Read(r, binary.LittleEndian, v)
+		}
+	}
+	// fits checks that count items of size bytes are left to read, so that a
+	// corrupt count fails before allocating memory for it
+	fits := func(what string, count, size uint64) {
+		if err == nil && count > uint64(r.Len())/size {
+			err = fmt.Errorf("%s: %d items do not fit in the %d bytes left", what, count, r.Len())
+		}
+	}
+	var numLayers uint32
+	read(&numLayers)
+	// every layer holds at least its two sizes
+	fits("layers", uint64(numLayers), 8)
+	if err != nil {
+		return fmt.Errorf("reading binary MLP: %w", err)
+	}
+	layers := make([]*Layer, 0, numLayers)
+	for i := 0; i < int(numLayers) && err == nil; i++ {
+		var nin, nout uint32
+		var name string
+		var nonlin, norm bool
+		var dropout float64
+		read(&nin)
+		read(&nout)
+		if version == 1 {
+			read(&nonlin)
+		} else {
+			var size uint16
+			read(&size)
+			fits("activation name", uint64(size), 1)
+			if err != nil {
+				break
+			}
+			nameBytes := make([]byte, size)
+			read(nameBytes)
+			name = string(nameBytes)
+		}
+		if version >= 3 {
+			read(&dropout)
+			read(&norm)
+		}
+		if err != nil {
+			break
+		}
+		var act Activation
+		if act, err = layerActivation(name, nonlin); err != nil {
+			break
+		}
+		// the bias and weights of every neuron
+		fits(fmt.Sprintf("layer %d neurons", i), uint64(nout), 8*(uint64(nin)+1))
+		if err != nil {
+			break
+		}
+		layer := &Layer{neurons: make([]*Neuron, nout)}
+		for k := range layer.neurons {
+			var b float64
+			w := make([]float64, nin)
+			read(&b)
+			read(w)
+			layer.neurons[k] = restoreNeuron(w, b, act)
+		}
+		if norm {
+			var momentum, eps float64
+			stats := make([][]float64, 4)
+			read(&momentum)
+			read(&eps)
+			fits(fmt.Sprintf("layer %d batch norm", i), uint64(nout), 8*4)
+			if err != nil {
+				break
+			}
+			for k := range stats {
+				stats[k] = make([]float64, nout)
+				read(stats[k])
+			}
+			layer.norm = restoreBatchNorm(stats[0], stats[1], stats[2], stats[3], momentum, eps)
+		}
+		if dropout < 0 || dropout >= 1 {
+			err = fmt.Errorf("layer %d: invalid dropout probability %v", i, dropout)
+		} else if dropout > 0 {
+			layer.dropout = NewDropout(dropout, nil)
+		}
+		layers = append(layers, layer)
+	}
+	if err != nil {
+		return fmt.Errorf("reading binary MLP: %w", err)
+	}
+	if r.Len() != 0 {
+		return fmt.Errorf("reading binary MLP: %d trailing bytes", r.Len())
+	}
+	if err := checkLayerSizes(layers); err != nil {
+		return err
+	}
+	l.layers = layers
+	return nil
+}
+
+// Save writes the MLP to filename, as JSON if the file has a .json extension
+// and in the binary format otherwise
+func (l *MLP) Save(filename string) error {
+	var data []byte
+	var err error
+	if filepath.Ext(filename) == ".json" {
+		data, err = json.MarshalIndent(l, "", "  ")
+	} else {
+		data, err = l.MarshalBinary()
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0o644)
+}
+
+// LoadMLP reads an MLP written by Save
+func LoadMLP(filename string) (*MLP, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	mlp := &MLP{}
+	if filepath.Ext(filename) == ".json" {
+		err = json.Unmarshal(data, mlp)
+	} else {
+		err = mlp.UnmarshalBinary(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return mlp, nil
+}
+
+func restoreNeuron(w []float64, b float64, act Activation) *Neuron {
+	n := &Neuron{w: make([]*Value, len(w)), b: NewValue(b), act: act}
+	for i := range w {
+		n.w[i] = NewValue(w[i])
+	}
+	return n
+}
+
+func restoreBatchNorm(gamma, beta, mean, variance []float64, momentum, eps float64) *BatchNorm {
+	b := NewBatchNorm(len(gamma))
+	for i := range gamma {
+		b.Gamma[i].Data = gamma[i]
+		b.Beta[i].Data = beta[i]
+	}
+	copy(b.RunningMean, mean)
+	copy(b.RunningVar, variance)
+	b.Momentum, b.Eps = momentum, eps
+	return b
+}
+
+func valuesData(values []*Value) []float64 {
+	data := make([]float64, len(values))
+	for i, v := range values {
+		data[i] = v.Data
+	}
+	return data
+}
+
+// layerActivation resolves a saved activation name, falling back to the nonlin
+// flag of the files written before activations had names
+func layerActivation(name string, nonlin bool) (Activation, error) {
+	if name == "" && nonlin {
+		return ReLU, nil
+	}
+	return ActivationByName(name)
+}
+
+// checkLayerSizes makes sure the output of every layer fits the input of the next one
+func checkLayerSizes(layers []*Layer) error {
+	for i, layer := range layers {
+		if len(layer.neurons) == 0 {
+			return fmt.Errorf("layer %d has no neurons", i)
+		}
+		if i > 0 && layer.nin() != len(layers[i-1].neurons) {
+			return fmt.Errorf("layer %d expects %d inputs but layer %d has %d outputs",
+				i, layer.nin(), i-1, len(layers[i-1].neurons))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package micrograd
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+func checkSameForward(t *testing.T, want, got *MLP) {
+	t.Helper()
+	inputs := [][]float64{{0.5, -1.25}, {2.0, 0.1}, {-0.3, -0.7}}
+	for _, in := range inputs {
+		x := []*Value{NewValue(in[0]), NewValue(in[1])}
+		a := want.Forward(x)
+		b := got.Forward(x)
+		if len(a) != len(b) {
+			t.Fatalf("got %d outputs, want %d", len(b), len(a))
+		}
+		for i := range a {
+			if a[i].Data != b[i].Data {
+				t.Errorf("input %v output %d: got %v, want %v", in, i, b[i].Data, a[i].Data)
+			}
+		}
+	}
+}
+
+func TestMLPJSONRoundTrip(t *testing.T) {
+	mlp := NewMLP(2, []int{4, 3, 2})
+	data, err := json.Marshal(mlp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := &MLP{}
+	if err := json.Unmarshal(data, loaded); err != nil {
+		t.Fatal(err)
+	}
+	checkSameForward(t, mlp, loaded)
+}
+
+func TestMLPBinaryRoundTrip(t *testing.T) {
+	mlp := NewMLP(2, []int{4, 3, 2})
+	data, err := mlp.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := &MLP{}
+	if err := loaded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	checkSameForward(t, mlp, loaded)
+
+	if err := loaded.UnmarshalBinary(data[:len(data)-3]); err == nil {
+		t.Error("expected an error for truncated data")
+	}
+}
+
+func TestMLPRegularizedRoundTrip(t *testing.T) {
+	mlp := NewMLP(2, []int{4, 3, 2}, WithSeed(4), WithBatchNorm(), WithDropout(0.2))
+	mlp.ForwardBatch([][]*Value{{NewValue(0.5), NewValue(-1)}, {NewValue(1), NewValue(0.2)}})
+	mlp.SetTraining(false)
+	for _, name := range []string{"mlp.json", "mlp.bin"} {
+		filename := filepath.Join(t.TempDir(), name)
+		if err := mlp.Save(filename); err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := LoadMLP(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if loaded.String() != mlp.String() {
+			t.Errorf("%s: loaded %v, want %v", name, loaded, mlp)
+		}
+		loaded.SetTraining(false)
+		checkSameForward(t, mlp, loaded)
+	}
+}
+
+func TestMLPSaveLoad(t *testing.T) {
+	mlp := NewMLP(2, []int{5, 1})
+	for _, name := range []string{"mlp.json", "mlp.bin"} {
+		filename := filepath.Join(t.TempDir(), name)
+		if err := mlp.Save(filename); err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := LoadMLP(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkSameForward(t, mlp, loaded)
+		if len(loaded.Parameters()) != len(mlp.Parameters()) {
+			t.Errorf("%s: got %d parameters, want %d", name, len(loaded.Parameters()), len(mlp.Parameters()))
+		}
+	}
+}
+
+func TestMLPUnmarshalRejectsMismatchedLayers(t *testing.T) {
+	data := `{"layers":[{"nin":2,"nout":1,"nonlin":true,"neurons":[{"w":[1,2],"b":0}]},
+		{"nin":3,"nout":1,"nonlin":false,"neurons":[{"w":[1,2,3],"b":0}]}]}`
+	if err := json.Unmarshal([]byte(data), &MLP{}); err == nil {
+		t.Error("expected an error for mismatched layer sizes")
+	}
+}
+
+func TestMLPUnmarshalBinaryRejectsCorruptInput(t *testing.T) {
+	mlp := NewMLP(2, []int{3, 1}, WithBatchNorm())
+	data, err := mlp.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for n := len(binaryMagic) + 1; n < len(data); n++ {
+		if err := (&MLP{}).UnmarshalBinary(data[:n]); err == nil {
+			t.Errorf("no error for input truncated to %d bytes", n)
+		}
+	}
+
+	// a huge layer count, then a huge neuron count, must fail without allocating
+	header := len(binaryMagic) + 1
+	corrupt := append([]byte(nil), data...)
+	binary.LittleEndian.PutUint32(corrupt[header:], math.MaxUint32)
+	if err := (&MLP{}).UnmarshalBinary(corrupt); err == nil {
+		t.Error("no error for a corrupt layer count")
+	}
+	corrupt = append([]byte(nil), data...)
+	binary.LittleEndian.PutUint32(corrupt[header+8:], math.MaxUint32)
+	if err := (&MLP{}).UnmarshalBinary(corrupt); err == nil {
+		t.Error("no error for a corrupt neuron count")
+	}
+}
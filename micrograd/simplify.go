@@ -0,0 +1,227 @@
+package micrograd
+
+import (
+	"errors"
+	"fmt"
+)
+
+// SimplifyStats reports what Simplify did to a graph
+type SimplifyStats struct {
+	// Before and After are the number of values in the graph
+	Before, After int
+	// Folded counts the values computed only from constants that became a single constant
+	Folded int
+	// Merged counts the additions merged into a larger sum
+	Merged int
+	// Dropped counts the identity operations removed, like x*1, x+0 or x**1
+	Dropped int
+}
+
+// Saved is the number of values Simplify removed
+func (s SimplifyStats) Saved() int {
+	return s.Before - s.After
+}
+
+func (s SimplifyStats) String() string {
+	percent := 0.0
+	if s.Before > 0 {
+		percent = 100 * float64(s.Saved()) / float64(s.Before)
+	}
+	return fmt.Sprintf("%d -> %d values (%.1f%% saved): %d folded, %d merged, %d dropped",
+		s.Before, s.After, percent, s.Folded, s.Merged, s.Dropped)
+}
+
+// Simplify rebuilds the graph rooted at root with fewer values:
+//   - values computed only from constants (see Constant) become one constant
+//   - chains of additions become a single n-ary sum, with their constants added up
+//   - multiplications by constants are combined, so Neg(Neg(x)) is x
+//   - identity operations (x*1, x+0, x**1) are dropped
+//
+// Leaves that are not constants are shared with the original graph, so calling
+// Backward on the simplified root gives them the same gradients as the original
+// one, up to rounding. The constants themselves do not receive gradients.
+// The original graph is left untouched. Values computed without gradients (see
+// NoGradValue) have no graph to simplify and give an error.
+func Simplify(root *Value) (*Value, SimplifyStats, error) {
+	if root.nograd {
+		return nil, SimplifyStats{}, errors.New("cannot simplify a value computed without gradients")
+	}
+	topo := root.topo()
+	stats := SimplifyStats{Before: len(topo)}
+	// values used more than once are not merged into their parents, so that the
+	// work they share is not duplicated
+	uses := make(map[*Value]int, len(topo))
+	for _, v := range topo {
+		for _, child := range v.prev {
+			uses[child]++
+		}
+	}
+
+	// simple maps the original values to the simplified ones, which are used
+	// as often as all the originals they replace
+	simple := make(map[*Value]*Value, len(topo))
+	simpleUses := make(map[*Value]int, len(topo))
+	for _, v := range topo {
+		if len(v.prev) > 0 {
+			out, err := simplifyValue(v, simple, simpleUses, &stats)
+			if err != nil {
+				return nil, stats, err
+			}
+			simple[v] = out
+		} else {
+			simple[v] = v
+		}
+		simpleUses[simple[v]] += uses[v]
+	}
+	out := simple[root]
+	stats.After = len(out.topo())
+	return out, stats, nil
+}
+
+// simplifyValue builds the simplified version of v from its simplified children
+func simplifyValue(v *Value, simple map[*Value]*Value, uses map[*Value]int, stats *SimplifyStats) (*Value, error) {
+	children := make([]*Value, len(v.prev))
+	constant := true
+	for i, child := range v.prev {
+		children[i] = simple[child]
+		constant = constant && children[i].constant
+	}
+	code, err := opcodeOf(v)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case constant:
+		out, err := rebuild(code, v.arg, children)
+		if err != nil {
+			return nil, err
+		}
+		stats.Folded++
+		return folded(out.Data, children...), nil
+	case code == opAdd || code == opSum:
+		return simplifySum(children, uses, stats), nil
+	case code == opMul:
+		return simplifyMul(children, uses, stats), nil
+	case code == opPow && v.arg == 1:
+		stats.Dropped++
+		return children[0], nil
+	}
+	return rebuild(code, v.arg, children)
+}
+
+// simplifySum flattens the additions and sums among the terms that are not used
+// anywhere else into one sum and adds up its constant terms
+func simplifySum(children []*Value, uses map[*Value]int, stats *SimplifyStats) *Value {
+	var terms, folds []*Value
+	constant := 0.0
+	var add func(term *Value)
+	add = func(term *Value) {
+		switch {
+		case term.constant:
+			constant += term.Data
+			folds = append(folds, term)
+		case (term.op == "+" || term.op == "sum") && uses[term] == 1:
+			stats.Merged++
+			for _, t := range term.prev {
+				add(t)
+			}
+		default:
+			terms = append(terms, term)
+		}
+	}
+	for _, child := range children {
+		add(child)
+	}
+	if len(folds) > 1 {
+		stats.Folded += len(folds) - 1
+	}
+	if c := folded(constant, folds...); constant != 0 || c.dataDependent {
+		terms = append(terms, c)
+	} else if len(folds) > 0 {
+		stats.Dropped++
+	}
+	switch len(terms) {
+	case 0:
+		return Constant(0)
+	case 1:
+		return terms[0]
+	case 2:
+		return terms[0].Add(terms[1])
+	}
+	return Sum(terms...)
+}
+
+// simplifyMul combines the constant factors of l*r, also those of a product
+// used only here, keeping the constant on the right like Neg does
+func simplifyMul(children []*Value, uses map[*Value]int, stats *SimplifyStats) *Value {
+	l, r := children[0], children[1]
+	if l.constant {
+		l, r = r, l
+	}
+	if !r.constant {
+		return l.Mul(r)
+	}
+	factor, folds := r.Data, []*Value{r}
+	// (x * c1) * c2 is x * (c1*c2)
+	if l.op == "*" && len(l.prev) == 2 && l.prev[1].constant && uses[l] == 1 {
+		factor *= l.prev[1].Data
+		folds = append(folds, l.prev[1])
+		l = l.prev[0]
+		stats.Folded++
+	}
+	c := folded(factor, folds...)
+	if factor == 1 && !c.dataDependent {
+		stats.Dropped++
+		return l
+	}
+	return l.Mul(c)
+}
+
+// folded makes the constant that replaces the constants from, keeping track of
+// the ones picked from the Data of the graph (see Compile)
+func folded(data float64, from ...*Value) *Value {
+	c := Constant(data)
+	for _, v := range from {
+		c.dataDependent = c.dataDependent || v.dataDependent
+	}
+	return c
+}
+
+// unaryOps are the engine ops taking a single value and no parameter, by opcode
+var unaryOps = map[opcode]func(*Value) *Value{
+	opRelu:         (*Value).Relu,
+	opExp:          (*Value).Exp,
+	opLog:          (*Value).Log,
+	opSqrt:         (*Value).Sqrt,
+	opAbs:          (*Value).Abs,
+	opSin:          (*Value).Sin,
+	opCos:          (*Value).Cos,
+	opTanh:         (*Value).Tanh,
+	opSigmoid:      (*Value).Sigmoid,
+	opSilu:         (*Value).Silu,
+	opGelu:         (*Value).Gelu,
+	opNormalCDF:    (*Value).NormalCDF,
+	opSoftplus:     (*Value).Softplus,
+	opLogSigmoid:   (*Value).LogSigmoid,
+	opStopGradient: (*Value).StopGradient,
+}
+
+// rebuild applies the op with the given code and parameter to new children
+func rebuild(code opcode, arg float64, children []*Value) (*Value, error) {
+	switch code {
+	case opAdd:
+		return children[0].Add(children[1]), nil
+	case opMul:
+		return children[0].Mul(children[1]), nil
+	case opPow:
+		return children[0].Pow(arg), nil
+	case opLeakyRelu:
+		return children[0].LeakyRelu(arg), nil
+	case opSum:
+		return Sum(children...), nil
+	}
+	if op, ok := unaryOps[code]; ok {
+		return op(children[0]), nil
+	}
+	return nil, fmt.Errorf("cannot rebuild opcode %d", code)
+}
@@ -0,0 +1,112 @@
+package micrograd
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSimplifyRules(t *testing.T) {
+	a, b, c, d := NewValue(0.5), NewValue(-1.5), NewValue(2.0), NewValue(3.0)
+	vars := map[string]*Value{"a": a, "b": b, "c": c, "d": d}
+	tests := []struct {
+		src, want string
+	}{
+		{"-(-a)", "a"},
+		{"a + b + c + d", "sum(a, b, c, d)"},
+		{"a - b", "a - b"},
+		{"(2 * 3 + 1) * a", "a * 7"},
+		{"a * 1 + 0", "a"},
+		{"a ** 1 + b", "a + b"},
+		{"a + 1 + b + 2", "sum(a, b, 3)"},
+		{"-(2 * a)", "a * -2"},
+		{"tanh(a / 4 + exp(0))", "tanh(a * 0.25 + 1)"},
+	}
+	for _, tt := range tests {
+		v, err := Parse(tt.src, vars)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, _, err := Simplify(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := Infix(s, vars); got != tt.want {
+			t.Errorf("Simplify(%s) = %s, want %s", tt.src, got, tt.want)
+		}
+		if math.Abs(s.Data-v.Data) > 1e-12 {
+			t.Errorf("Simplify(%s) data %v, want %v", tt.src, s.Data, v.Data)
+		}
+	}
+}
+
+func TestSimplifyKeepsSharedValues(t *testing.T) {
+	a, b, c := NewValue(1), NewValue(2), NewValue(3)
+	shared := a.Add(b)
+	root := shared.Add(c).Mul(shared.Tanh())
+	s, _, err := Simplify(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// shared stays one value used twice instead of being merged into shared + c
+	if sum := s.prev[0]; sum.op != "+" || sum.prev[0].op != "+" {
+		t.Errorf("shared addition was merged: %s", Infix(s, map[string]*Value{"a": a, "b": b, "c": c}))
+	}
+}
+
+func TestSimplifyKeepsGradients(t *testing.T) {
+	mlp := NewMLP(3, []int{4, 4, 1}, WithSeed(5), WithHiddenActivation(Tanh))
+	for _, tt := range []struct {
+		name string
+		f    func([]*Value) *Value
+	}{
+		{"every op", everyOp},
+		{"mlp loss", func(v []*Value) *Value {
+			out := mlp.Forward(v)[0]
+			return Mean(out.Sub(NewValue(0.5)).Pow(2), v[0].Neg().Neg().Mul(out).Div(Constant(4)))
+		}},
+	} {
+		x := []float64{0.7, -1.3, 2.1}
+		original := []*Value{NewValue(x[0]), NewValue(x[1]), NewValue(x[2])}
+		inputs := []*Value{NewValue(x[0]), NewValue(x[1]), NewValue(x[2])}
+		params := mlp.Parameters()
+
+		want := tt.f(original)
+		want.Backward()
+		wantGrads := make([]float64, len(params))
+		for i, p := range params {
+			wantGrads[i] = p.Grad
+			p.Grad = 0
+		}
+
+		s, stats, err := Simplify(tt.f(inputs))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.Saved() <= 0 || stats.After != len(s.topo()) {
+			t.Errorf("%s: stats %v", tt.name, stats)
+		}
+		s.Backward()
+		if math.Abs(s.Data-want.Data) > 1e-12 {
+			t.Errorf("%s: data %v, want %v", tt.name, s.Data, want.Data)
+		}
+		for i := range inputs {
+			if math.Abs(inputs[i].Grad-original[i].Grad) > 1e-12 {
+				t.Errorf("%s: input %d grad %v, want %v", tt.name, i, inputs[i].Grad, original[i].Grad)
+			}
+		}
+		for i, p := range params {
+			if math.Abs(p.Grad-wantGrads[i]) > 1e-12 {
+				t.Errorf("%s: parameter %d grad %v, want %v", tt.name, i, p.Grad, wantGrads[i])
+			}
+			p.Grad = 0
+		}
+	}
+}
+
+func TestSimplifyNoGrad(t *testing.T) {
+	x := NewValue(0.5)
+	f := x.Mul(NoGradValue(2)).Add(Constant(0))
+	if _, _, err := Simplify(f); err == nil {
+		t.Error("expected an error for a value computed without gradients")
+	}
+}
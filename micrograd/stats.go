@@ -0,0 +1,164 @@
+package micrograd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unsafe"
+)
+
+// GraphStats describes the graph under a root Value, see Analyze
+type GraphStats struct {
+	// Nodes is the number of values, Leaves how many of them have no children
+	// and Constants how many of the leaves were made by Constant
+	Nodes     int `json:"nodes"`
+	Leaves    int `json:"leaves"`
+	Constants int `json:"constants"`
+	// Edges counts the links from values to their children
+	Edges int `json:"edges"`
+	// Ops counts the values by op, "leaf" and "const" for the leaves
+	Ops map[string]int `json:"ops"`
+	// Depth is the length of the longest path from a leaf to the root
+	Depth int `json:"depth"`
+	// MaxFanOut is the largest number of values using the same value, MeanFanOut
+	// the average over every value but the root
+	MaxFanOut  int     `json:"max_fan_out"`
+	MeanFanOut float64 `json:"mean_fan_out"`
+	// Closures counts the backward functions that hold on to values
+	Closures int `json:"closures"`
+	// Bytes estimates the heap memory kept alive by the graph: the values, their
+	// children slices, their backward closures and their op names
+	Bytes int `json:"bytes"`
+}
+
+// opNames groups the ops that carry a parameter in their name, like "^2"
+var opNames = map[opcode]string{
+	opPow:       "pow",
+	opLeakyRelu: "leaky_relu",
+}
+
+// Analyze walks the graph rooted at root once and collects its statistics.
+// It is cheap enough to run on the loss of every training step.
+func Analyze(root *Value) GraphStats {
+	topo := root.topo()
+	s := GraphStats{Nodes: len(topo), Ops: map[string]int{}}
+	depth := make(map[*Value]int, len(topo))
+	uses := make(map[*Value]int, len(topo))
+	for _, v := range topo {
+		s.Bytes += valueBytes(v)
+		if len(v.prev) == 0 {
+			s.Leaves++
+			if v.constant {
+				s.Constants++
+				s.Ops["const"]++
+			} else {
+				s.Ops["leaf"]++
+			}
+			continue
+		}
+
+		name := v.op
+		if code, err := opcodeOf(v); err == nil {
+			if n, ok := opNames[code]; ok {
+				name = n
+			}
+			if code != opStopGradient {
+				s.Closures++
+			}
+		}
+		s.Ops[name]++
+		s.Edges += len(v.prev)
+		d := 0
+		for _, child := range v.prev {
+			uses[child]++
+			d = max(d, depth[child]+1)
+		}
+		depth[v] = d
+	}
+	s.Depth = depth[root]
+	for _, n := range uses {
+		s.MaxFanOut = max(s.MaxFanOut, n)
+	}
+	if s.Nodes > 1 {
+		s.MeanFanOut = float64(s.Edges) / float64(s.Nodes-1)
+	}
+	return s
+}
+
+// valueBytes estimates the memory held by v, rounding every allocation up to
+// the size classes of the Go allocator
+func valueBytes(v *Value) int {
+	n := allocSize(int(unsafe.Sizeof(*v)))
+	if cap(v.prev) > 0 {
+		n += allocSize(cap(v.prev) * int(unsafe.Sizeof(v)))
+	}
+	code, err := opcodeOf(v)
+	if len(v.prev) == 0 || err != nil || code == opStopGradient {
+		return n
+	}
+	// the closure holds a code pointer and the captured out and children
+	// (Sum captures its slice of children instead)
+	captured := len(v.prev) + 1
+	if code == opSum {
+		captured = 4
+	}
+	n += allocSize(8 * (captured + 1))
+	if _, ok := opNames[code]; ok {
+		// the op name of parametrized ops is formatted for every value
+		n += allocSize(len(v.op))
+	}
+	return n
+}
+
+// sizeClasses are the smallest size classes of the Go allocator
+var sizeClasses = []int{8, 16, 24, 32, 48, 64, 80, 96, 112, 128, 144, 160, 176, 192, 208, 224, 240, 256}
+
+func allocSize(n int) int {
+	for _, c := range sizeClasses {
+		if n <= c {
+			return c
+		}
+	}
+	return (n + 7) &^ 7
+}
+
+// String formats the statistics as a text report, with the ops from the most to
+// the least common
+func (s GraphStats) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "nodes %d (leaves %d, constants %d), edges %d, depth %d\n", s.Nodes, s.Leaves, s.Constants, s.Edges, s.Depth)
+	fmt.Fprintf(&sb, "fan-out max %d, mean %.2f\n", s.MaxFanOut, s.MeanFanOut)
+	fmt.Fprintf(&sb, "closures %d\n", s.Closures)
+	perNode := 0
+	if s.Nodes > 0 {
+		perNode = s.Bytes / s.Nodes
+	}
+	fmt.Fprintf(&sb, "estimated memory %s (%d B per node)\n", formatBytes(s.Bytes), perNode)
+	ops := make([]string, 0, len(s.Ops))
+	for op := range s.Ops {
+		ops = append(ops, op)
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		if s.Ops[ops[i]] != s.Ops[ops[j]] {
+			return s.Ops[ops[i]] > s.Ops[ops[j]]
+		}
+		return ops[i] < ops[j]
+	})
+	sb.WriteString("ops:\n")
+	for _, op := range ops {
+		fmt.Fprintf(&sb, "  %-12s %8d\n", op, s.Ops[op])
+	}
+	return sb.String()
+}
+
+func formatBytes(n int) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1f GiB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
@@ -0,0 +1,67 @@
+package micrograd
+
+import (
+	"encoding/json"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func TestAnalyze(t *testing.T) {
+	a, b, c := NewValue(2), NewValue(-3), NewValue(10)
+	e := a.Mul(b)
+	d := e.Add(c).Add(e.Pow(2)).Neg()
+	s := Analyze(d)
+	want := GraphStats{
+		Nodes: 9, Leaves: 4, Constants: 1, Edges: 9,
+		Ops:       map[string]int{"leaf": 3, "const": 1, "*": 2, "+": 2, "pow": 1},
+		Depth:     4,
+		MaxFanOut: 2, MeanFanOut: 9.0 / 8,
+		Closures: 5,
+	}
+	s.Bytes = 0
+	if !reflect.DeepEqual(s, want) {
+		t.Errorf("got %+v\nwant %+v", s, want)
+	}
+	report := Analyze(d).String()
+	for _, line := range []string{"nodes 9 (leaves 4, constants 1), edges 9, depth 4", "closures 5", "  leaf                3"} {
+		if !strings.Contains(report, line) {
+			t.Errorf("report misses %q:\n%s", line, report)
+		}
+	}
+}
+
+func TestAnalyzeBytes(t *testing.T) {
+	mlp := NewMLP(4, []int{16, 16, 1}, WithSeed(1))
+	x := []*Value{NewValue(1), NewValue(2), NewValue(3), NewValue(4)}
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	loss := mlp.Forward(x)[0].Sub(NewValue(1)).Pow(2)
+	runtime.ReadMemStats(&after)
+
+	// the estimate leaves out the few temporary slices of Forward
+	allocated := float64(after.TotalAlloc - before.TotalAlloc)
+	s := Analyze(loss)
+	params := len(mlp.Parameters()) + len(x)
+	estimate := float64(s.Bytes - params*allocSize(int(unsafe.Sizeof(Value{}))))
+	if ratio := estimate / allocated; ratio < 0.8 || ratio > 1.2 {
+		t.Errorf("estimated %v bytes for the new values, %v allocated", estimate, allocated)
+	}
+}
+
+func TestGraphStatsJSON(t *testing.T) {
+	s := Analyze(everyOp([]*Value{NewValue(0.7), NewValue(-1.3), NewValue(2.1)}))
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back GraphStats
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(back, s) || !strings.Contains(string(data), `"max_fan_out"`) {
+		t.Errorf("round trip changed the stats: %s", data)
+	}
+}
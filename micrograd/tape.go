@@ -0,0 +1,282 @@
+package micrograd
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+type opcode uint8
+
+const (
+	opAdd opcode = iota
+	opMul
+	opPow
+	opRelu
+	opLeakyRelu
+	opExp
+	opLog
+	opSqrt
+	opAbs
+	opSin
+	opCos
+	opTanh
+	opSigmoid
+	opSilu
+	opGelu
+	opNormalCDF
+	opSoftplus
+	opLogSigmoid
+	opSum
+	opStopGradient
+)
+
+var opcodes = map[string]opcode{
+	"+":          opAdd,
+	"*":          opMul,
+	"ReLU":       opRelu,
+	"exp":        opExp,
+	"log":        opLog,
+	"sqrt":       opSqrt,
+	"abs":        opAbs,
+	"sin":        opSin,
+	"cos":        opCos,
+	"tanh":       opTanh,
+	"sigmoid":    opSigmoid,
+	"SiLU":       opSilu,
+	"GELU":       opGelu,
+	"Phi":        opNormalCDF,
+	"softplus":   opSoftplus,
+	"logsigmoid": opLogSigmoid,
+	"sum":        opSum,
+	"stopgrad":   opStopGradient,
+}
+
+// opcodeOf maps the op of a value built by the engine to its opcode
+func opcodeOf(v *Value) (opcode, error) {
+	if code, ok := opcodes[v.op]; ok {
+		return code, nil
+	}
+	switch {
+	case strings.HasPrefix(v.op, "^"):
+		return opPow, nil
+	case strings.HasPrefix(v.op, "LeakyReLU("):
+		return opLeakyRelu, nil
+	}
+	return 0, fmt.Errorf("cannot compile op %q", v.op)
+}
+
+// instruction computes slot out from the input slots a and b (or args for sums)
+type instruction struct {
+	op   opcode
+	out  int32
+	a, b int32
+	arg  float64
+	args []int32
+}
+
+// Tape is a Value graph compiled into a flat list of instructions over slots
+// holding data and gradients. It can be run again, without allocating, after
+// changing the Data of the leaves (inputs, parameters).
+type Tape struct {
+	// nodes holds the traced values in topological order, slot i is nodes[i]
+	nodes  []*Value
+	leaves []int32
+	code   []instruction
+	data   []float64
+	grad   []float64
+}
+
+// Compile traces the graph rooted at root once and turns it into a Tape.
+// Leaves (values without children) are read again on every Forward, so the
+// tape follows the updates an optimizer makes to parameters and the new data
+// written into the input values.
+//
+// The structure of the graph is fixed when it is traced. Replays only match a
+// freshly built graph if the code that built it did not pick its ops or
+// literals from the Data it saw. Compile rejects the graphs of Gradients, whose
+// slopes are picked that way, but cannot detect it in other code.
+func Compile(root *Value) (*Tape, error) {
+	topo := root.topo()
+	slots := make(map[*Value]int32, len(topo))
+	t := &Tape{
+		nodes: topo,
+		data:  make([]float64, len(topo)),
+		grad:  make([]float64, len(topo)),
+	}
+	for i, v := range topo {
+		slots[v] = int32(i)
+		if len(v.prev) == 0 {
+			if v.dataDependent {
+				return nil, errors.New("cannot compile a graph with constants picked from its Data, like the slopes of Gradients")
+			}
+			t.leaves = append(t.leaves, int32(i))
+			continue
+		}
+		code, err := opcodeOf(v)
+		if err != nil {
+			return nil, err
+		}
+		in := instruction{op: code, out: int32(i), a: slots[v.prev[0]], arg: v.arg}
+		switch code {
+		case opAdd, opMul:
+			in.b = slots[v.prev[1]]
+		case opSum:
+			in.args = make([]int32, len(v.prev))
+			for k, child := range v.prev {
+				in.args[k] = slots[child]
+			}
+		}
+		t.code = append(t.code, in)
+	}
+	return t, nil
+}
+
+// Len is the number of values on the tape
+func (t *Tape) Len() int {
+	return len(t.nodes)
+}
+
+// Forward reads the Data of the leaves, recomputes every value and stores the
+// results in the Data of the traced values. It returns the Data of the root.
+func (t *Tape) Forward() float64 {
+	for _, i := range t.leaves {
+		t.data[i] = t.nodes[i].Data
+	}
+	d := t.data
+	for k := range t.code {
+		in := &t.code[k]
+		x := d[in.a]
+		var out float64
+		switch in.op {
+		case opAdd:
+			out = x + d[in.b]
+		case opMul:
+			out = x * d[in.b]
+		case opPow:
+			out = math.Pow(x, in.arg)
+		case opRelu:
+			out = math.Max(0, x)
+		case opLeakyRelu:
+			out = x
+			if out < 0 {
+				out *= in.arg
+			}
+		case opExp:
+			out = math.Exp(x)
+		case opLog:
+			out = math.Log(x)
+		case opSqrt:
+			out = math.Sqrt(x)
+		case opAbs:
+			out = math.Abs(x)
+		case opSin:
+			out = math.Sin(x)
+		case opCos:
+			out = math.Cos(x)
+		case opTanh:
+			out = math.Tanh(x)
+		case opSigmoid:
+			out = sigmoid(x)
+		case opSilu:
+			out = x * sigmoid(x)
+		case opGelu:
+			out = x * normalCDF(x)
+		case opNormalCDF:
+			out = normalCDF(x)
+		case opSoftplus:
+			out = math.Max(x, 0) + math.Log1p(math.Exp(-math.Abs(x)))
+		case opLogSigmoid:
+			out = math.Min(x, 0) - math.Log1p(math.Exp(-math.Abs(x)))
+		case opSum:
+			out = 0.0
+			for _, a := range in.args {
+				out += d[a]
+			}
+		case opStopGradient:
+			out = x
+		}
+		d[in.out] = out
+	}
+	for i, v := range t.nodes {
+		v.Data = d[i]
+	}
+	return d[len(d)-1]
+}
+
+// Backward propagates the gradient of the root like Value.Backward: the root
+// gradient is 1, the other traced values start from 0 and the leaves accumulate
+// on top of their current Grad. Forward must have been run first.
+func (t *Tape) Backward() {
+	d, g := t.data, t.grad
+	for i := range g {
+		g[i] = 0
+	}
+	for _, i := range t.leaves {
+		g[i] = t.nodes[i].Grad
+	}
+	g[len(g)-1] = 1.0
+	for k := len(t.code) - 1; k >= 0; k-- {
+		in := &t.code[k]
+		x, out, grad := d[in.a], d[in.out], g[in.out]
+		switch in.op {
+		case opAdd:
+			g[in.a] += grad
+			g[in.b] += grad
+		case opMul:
+			g[in.a] += d[in.b] * grad
+			g[in.b] += x * grad
+		case opPow:
+			g[in.a] += in.arg * math.Pow(x, in.arg-1) * grad
+		case opRelu:
+			if x > 0 {
+				g[in.a] += grad
+			}
+		case opLeakyRelu:
+			if x > 0 {
+				g[in.a] += grad
+			} else {
+				g[in.a] += in.arg * grad
+			}
+		case opExp:
+			g[in.a] += out * grad
+		case opLog:
+			g[in.a] += grad / x
+		case opSqrt:
+			g[in.a] += grad / (2 * out)
+		case opAbs:
+			if x > 0 {
+				g[in.a] += grad
+			} else if x < 0 {
+				g[in.a] -= grad
+			}
+		case opSin:
+			g[in.a] += math.Cos(x) * grad
+		case opCos:
+			g[in.a] -= math.Sin(x) * grad
+		case opTanh:
+			g[in.a] += (1 - out*out) * grad
+		case opSigmoid:
+			g[in.a] += out * (1 - out) * grad
+		case opSilu:
+			s := sigmoid(x)
+			g[in.a] += s * (1 + x*(1-s)) * grad
+		case opGelu:
+			g[in.a] += (normalCDF(x) + x*normalPDF(x)) * grad
+		case opNormalCDF:
+			g[in.a] += normalPDF(x) * grad
+		case opSoftplus:
+			g[in.a] += sigmoid(x) * grad
+		case opLogSigmoid:
+			g[in.a] += sigmoid(-x) * grad
+		case opSum:
+			for _, a := range in.args {
+				g[a] += grad
+			}
+		}
+	}
+	for i, v := range t.nodes {
+		v.Grad = g[i]
+	}
+}
@@ -0,0 +1,133 @@
+package micrograd
+
+import "testing"
+
+// everyOp builds a graph that goes through every op the tape supports
+func everyOp(v []*Value) *Value {
+	a, b, c := v[0], v[1], v[2]
+	terms := []*Value{
+		a.Add(b).Mul(c),
+		a.Pow(3).Add(b.Pow(-2)),
+		a.Sub(b).Relu().Add(b.Sub(a).LeakyRelu(0.1)),
+		c.Exp().Log().Sqrt().Abs(),
+		a.Sin().Mul(b.Cos()),
+		a.Tanh().Add(b.Sigmoid()).Add(c.Silu()).Add(a.Gelu()).Add(b.NormalCDF()),
+		b.Softplus().Add(c.LogSigmoid()),
+		a.Div(c).Neg(),
+	}
+	return Mean(terms...)
+}
+
+func TestTapeMatchesEngine(t *testing.T) {
+	inputs := []*Value{NewValue(0.7), NewValue(-1.3), NewValue(2.1)}
+	tape, err := Compile(everyOp(inputs))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, x := range [][]float64{{0.7, -1.3, 2.1}, {-0.4, 0.9, 0.3}, {1.5, 2.5, 0.6}} {
+		for i := range inputs {
+			inputs[i].Data = x[i]
+			inputs[i].Grad = 0
+		}
+		got := tape.Forward()
+		tape.Backward()
+
+		fresh := []*Value{NewValue(x[0]), NewValue(x[1]), NewValue(x[2])}
+		want := everyOp(fresh)
+		want.Backward()
+		if got != want.Data {
+			t.Errorf("x=%v: tape forward %v, engine %v", x, got, want.Data)
+		}
+		for i := range inputs {
+			if inputs[i].Grad != fresh[i].Grad {
+				t.Errorf("x=%v: input %d tape grad %v, engine %v", x, i, inputs[i].Grad, fresh[i].Grad)
+			}
+		}
+	}
+}
+
+func TestTapeTrainsLikeEngine(t *testing.T) {
+	x := [][]float64{{0.5, -1.0}, {1.5, 0.3}, {-0.7, 0.8}}
+	y := []float64{1, -1, 1}
+	loss := func(mlp *MLP) *Value {
+		losses := make([]*Value, len(x))
+		for i := range x {
+			score := mlp.Forward([]*Value{NewValue(x[i][0]), NewValue(x[i][1])})[0]
+			losses[i] = NewValue(1).Sub(score.Mul(NewValue(y[i]))).Relu()
+		}
+		return Mean(losses...)
+	}
+	step := func(mlp *MLP) {
+		for _, p := range mlp.Parameters() {
+			p.Data -= 0.1 * p.Grad
+		}
+	}
+
+	dynamic := NewMLP(2, []int{4, 1}, WithSeed(5))
+	compiled := NewMLP(2, []int{4, 1}, WithSeed(5))
+	tape, err := Compile(loss(compiled))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k := 0; k < 20; k++ {
+		dynamic.ZeroGrad()
+		loss(dynamic).Backward()
+		step(dynamic)
+
+		compiled.ZeroGrad()
+		tape.Forward()
+		tape.Backward()
+		step(compiled)
+	}
+	want, got := dynamic.Parameters(), compiled.Parameters()
+	for i := range want {
+		if want[i].Data != got[i].Data {
+			t.Fatalf("parameter %d: tape %v, engine %v", i, got[i].Data, want[i].Data)
+		}
+	}
+}
+
+func TestTapeDoesNotAllocate(t *testing.T) {
+	mlp := NewMLP(2, []int{8, 8, 1}, WithSeed(1))
+	tape, err := Compile(mlp.Forward([]*Value{NewValue(0.5), NewValue(-0.5)})[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	allocs := testing.AllocsPerRun(10, func() {
+		tape.Forward()
+		tape.Backward()
+	})
+	if allocs != 0 {
+		t.Errorf("got %v allocations per run, want 0", allocs)
+	}
+}
+
+func TestCompileUnknownOp(t *testing.T) {
+	v := makeValue(1, []*Value{NewValue(1)}, "mystery")
+	if _, err := Compile(v); err == nil {
+		t.Error("expected an error for an unknown op")
+	}
+}
+
+func BenchmarkMLPLoss(b *testing.B) {
+	mlp := NewMLP(2, []int{16, 16, 1}, WithSeed(1))
+	x := []*Value{NewValue(0.5), NewValue(-0.5)}
+	b.Run("engine", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			mlp.ZeroGrad()
+			mlp.Forward(x)[0].Backward()
+		}
+	})
+	b.Run("tape", func(b *testing.B) {
+		tape, _ := Compile(mlp.Forward(x)[0])
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			mlp.ZeroGrad()
+			tape.Forward()
+			tape.Backward()
+		}
+	})
+}
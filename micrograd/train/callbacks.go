@@ -0,0 +1,246 @@
+package train
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"vdanciu_lang_model/micrograd"
+	"vdanciu_lang_model/micrograd/schedule"
+)
+
+// Callback is notified at the end of every epoch with the logs of that epoch
+type Callback interface {
+	OnEpochEnd(t *Trainer, epoch int, logs Logs)
+}
+
+// TrainBeginCallback is implemented by callbacks that need to reset their
+// state when Fit starts
+type TrainBeginCallback interface {
+	OnTrainBegin(t *Trainer)
+}
+
+// StepCallback is implemented by callbacks that look at every optimizer step,
+// after Backward on the loss of the batch and before the parameters are updated.
+// step counts the steps from the start of Fit.
+type StepCallback interface {
+	OnStep(t *Trainer, step int, loss *micrograd.Value)
+}
+
+// CallbackFunc adapts an ordinary function to a Callback
+type CallbackFunc func(t *Trainer, epoch int, logs Logs)
+
+func (f CallbackFunc) OnEpochEnd(t *Trainer, epoch int, logs Logs) {
+	f(t, epoch, logs)
+}
+
+// Logger prints the logs every Every epochs (every epoch if Every is 0)
+type Logger struct {
+	W     io.Writer
+	Every int
+}
+
+func (l Logger) OnEpochEnd(t *Trainer, epoch int, logs Logs) {
+	if l.Every > 1 && epoch%l.Every != 0 {
+		return
+	}
+	w := l.W
+	if w == nil {
+		w = os.Stdout
+	}
+	keys := make([]string, 0, len(logs))
+	for k := range logs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s %.6f", k, logs[k])
+	}
+	fmt.Fprintf(w, "epoch %v %s\n", epoch, strings.Join(parts, " "))
+}
+
+// Saver is implemented by models that can be written to disk, like micrograd.MLP
+type Saver interface {
+	Save(filename string) error
+}
+
+// Checkpoint saves the model after every epoch. Path may contain a %d verb for
+// the epoch number. With Monitor set and BestOnly, the model is only saved when
+// the monitored value improves.
+type Checkpoint struct {
+	Path     string
+	Monitor  string
+	BestOnly bool
+	Maximize bool
+	// Err holds the last error returned while saving
+	Err error
+
+	best monitor
+}
+
+func (c *Checkpoint) OnTrainBegin(t *Trainer) {
+	c.best = monitor{}
+	c.Err = nil
+}
+
+func (c *Checkpoint) OnEpochEnd(t *Trainer, epoch int, logs Logs) {
+	saver, ok := t.Model.(Saver)
+	if !ok {
+		c.Err = fmt.Errorf("model %T cannot be saved", t.Model)
+		return
+	}
+	if c.BestOnly && !c.best.improved(logs, c.Monitor, c.Maximize, 0) {
+		return
+	}
+	path := c.Path
+	if strings.Contains(path, "%d") {
+		path = fmt.Sprintf(path, epoch)
+	}
+	if err := saver.Save(path); err != nil {
+		c.Err = err
+	}
+}
+
+// EarlyStopping stops training when the monitored value (e.g. "val_loss") has
+// not improved by more than MinDelta for Patience epochs. With RestoreBest the
+// parameters of the best epoch are put back when training stops. Epochs whose
+// logs lack the monitored key, like "val_loss" without a validation set, are
+// not counted and set Err instead.
+type EarlyStopping struct {
+	Monitor     string
+	Patience    int
+	MinDelta    float64
+	Maximize    bool
+	RestoreBest bool
+	// StoppedEpoch is the epoch at which training was stopped, -1 if it ran to the end
+	StoppedEpoch int
+	// Err reports a monitored key missing from the logs
+	Err error
+
+	best       monitor
+	wait       int
+	bestParams []float64
+}
+
+func (e *EarlyStopping) OnTrainBegin(t *Trainer) {
+	e.StoppedEpoch = -1
+	e.Err = nil
+	e.best = monitor{}
+	e.wait = 0
+	e.bestParams = nil
+}
+
+func (e *EarlyStopping) OnEpochEnd(t *Trainer, epoch int, logs Logs) {
+	if _, ok := logs[e.Monitor]; !ok {
+		e.Err = fmt.Errorf("early stopping: epoch %d has no %q in its logs", epoch, e.Monitor)
+		return
+	}
+	if e.best.improved(logs, e.Monitor, e.Maximize, e.MinDelta) {
+		e.wait = 0
+		if e.RestoreBest {
+			e.bestParams = e.bestParams[:0]
+			for _, p := range t.Model.Parameters() {
+				e.bestParams = append(e.bestParams, p.Data)
+			}
+		}
+		return
+	}
+	e.wait++
+	if e.wait >= e.Patience {
+		e.StoppedEpoch = epoch
+		if e.RestoreBest && e.bestParams != nil {
+			for i, p := range t.Model.Parameters() {
+				p.Data = e.bestParams[i]
+			}
+		}
+		t.Stop()
+	}
+}
+
+// PlateauScheduler lowers the learning rate of the optimizer with a
+// schedule.ReduceOnPlateau fed from the monitored value
+type PlateauScheduler struct {
+	Scheduler *schedule.ReduceOnPlateau
+	Monitor   string
+}
+
+func (p PlateauScheduler) OnEpochEnd(t *Trainer, epoch int, logs Logs) {
+	value, ok := logs[p.Monitor]
+	if !ok {
+		return
+	}
+	t.Optimizer.SetLR(p.Scheduler.Observe(value))
+}
+
+// monitor tracks the best value seen for a log key
+type monitor struct {
+	best float64
+	seen bool
+}
+
+// improved records the value of key in logs and reports whether it beats the
+// best value so far by more than minDelta
+func (m *monitor) improved(logs Logs, key string, maximize bool, minDelta float64) bool {
+	value, ok := logs[key]
+	if !ok || math.IsNaN(value) {
+		return false
+	}
+	better := !m.seen ||
+		(maximize && value > m.best+minDelta) ||
+		(!maximize && value < m.best-minDelta)
+	if better {
+		m.best = value
+		m.seen = true
+	}
+	return better
+}
+
+// GraphProfiler analyzes the loss graph of every Every-th step (every step if
+// Every is 0) with micrograd.Analyze and writes the report to W (stdout if nil),
+// as one JSON object per line with JSON. At the end of every epoch it adds the
+// size of the last analyzed graph to the logs as "graph_nodes", "graph_depth"
+// and "graph_bytes", so it should come before a Logger.
+type GraphProfiler struct {
+	W     io.Writer
+	Every int
+	JSON  bool
+	// Last holds the statistics of the last analyzed step
+	Last micrograd.GraphStats
+	Err  error
+}
+
+func (p *GraphProfiler) OnStep(t *Trainer, step int, loss *micrograd.Value) {
+	if p.Every > 1 && step%p.Every != 0 {
+		return
+	}
+	p.Last = micrograd.Analyze(loss)
+	w := p.W
+	if w == nil {
+		w = os.Stdout
+	}
+	var err error
+	if p.JSON {
+		err = json.NewEncoder(w).Encode(struct {
+			Step int `json:"step"`
+			micrograd.GraphStats
+		}{step, p.Last})
+	} else {
+		_, err = fmt.Fprintf(w, "step %d graph\n%s", step, p.Last)
+	}
+	if err != nil && p.Err == nil {
+		p.Err = err
+	}
+}
+
+func (p *GraphProfiler) OnEpochEnd(t *Trainer, epoch int, logs Logs) {
+	if p.Last.Nodes == 0 {
+		return
+	}
+	logs["graph_nodes"] = float64(p.Last.Nodes)
+	logs["graph_depth"] = float64(p.Last.Depth)
+	logs["graph_bytes"] = float64(p.Last.Bytes)
+}
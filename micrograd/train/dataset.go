@@ -0,0 +1,30 @@
+package train
+
+import "math/rand"
+
+// Dataset holds one input row and one target per example
+type Dataset struct {
+	X [][]float64
+	Y []float64
+}
+
+func (d Dataset) Len() int {
+	return len(d.X)
+}
+
+// Subset returns the examples at the given indices
+func (d Dataset) Subset(indices []int) Dataset {
+	out := Dataset{X: make([][]float64, len(indices)), Y: make([]float64, len(indices))}
+	for i, idx := range indices {
+		out.X[i] = d.X[idx]
+		out.Y[i] = d.Y[idx]
+	}
+	return out
+}
+
+// Split shuffles the examples with r and holds out a fraction of them for validation
+func (d Dataset) Split(fraction float64, r *rand.Rand) (train, validation Dataset) {
+	perm := r.Perm(d.Len())
+	n := int(fraction * float64(d.Len()))
+	return d.Subset(perm[n:]), d.Subset(perm[:n])
+}
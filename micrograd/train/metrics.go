@@ -0,0 +1,120 @@
+package train
+
+import (
+	"fmt"
+	"strings"
+	"vdanciu_lang_model/micrograd"
+)
+
+// Metric scores a batch of model outputs against their targets
+type Metric func(outputs [][]*micrograd.Value, targets []float64) float64
+
+// BinaryAccuracy is the fraction of examples where the sign of the first output
+// matches the class of the target; targets can be in {-1, 1} or {0, 1}
+func BinaryAccuracy(outputs [][]*micrograd.Value, targets []float64) float64 {
+	correct := 0.0
+	for i := range outputs {
+		if (outputs[i][0].Data > 0) == (targets[i] > 0) {
+			correct += 1.0
+		}
+	}
+	return correct / float64(len(outputs))
+}
+
+// Accuracy is the fraction of examples whose largest output is at the index of
+// the class of the target, for models with one logit per class
+func Accuracy(outputs [][]*micrograd.Value, targets []float64) float64 {
+	correct := 0.0
+	for i := range outputs {
+		if Argmax(outputs[i]) == int(targets[i]) {
+			correct += 1.0
+		}
+	}
+	return correct / float64(len(outputs))
+}
+
+// Argmax returns the index of the largest value, the first one on ties
+func Argmax(values []*micrograd.Value) int {
+	best := 0
+	for i, v := range values {
+		if v.Data > values[best].Data {
+			best = i
+		}
+	}
+	return best
+}
+
+// ConfusionMatrix counts the examples of every class (rows) by predicted class (columns)
+type ConfusionMatrix [][]int
+
+// NewConfusionMatrix compares the argmax of every output with the class of its target
+func NewConfusionMatrix(outputs [][]*micrograd.Value, targets []float64, classes int) ConfusionMatrix {
+	m := make(ConfusionMatrix, classes)
+	for i := range m {
+		m[i] = make([]int, classes)
+	}
+	for i := range outputs {
+		m[int(targets[i])][Argmax(outputs[i])]++
+	}
+	return m
+}
+
+// Accuracy is the fraction of the examples on the diagonal, 0 for an empty matrix
+func (m ConfusionMatrix) Accuracy() float64 {
+	correct, total := 0, 0
+	for i := range m {
+		for j, n := range m[i] {
+			total += n
+			if i == j {
+				correct += n
+			}
+		}
+	}
+	return ratio(correct, total)
+}
+
+// Precision is the fraction of the examples predicted as class that are of
+// class, 0 if no example is predicted as class
+func (m ConfusionMatrix) Precision(class int) float64 {
+	predicted := 0
+	for i := range m {
+		predicted += m[i][class]
+	}
+	return ratio(m[class][class], predicted)
+}
+
+// Recall is the fraction of the examples of class that are predicted as class,
+// 0 if there is no example of class
+func (m ConfusionMatrix) Recall(class int) float64 {
+	actual := 0
+	for _, n := range m[class] {
+		actual += n
+	}
+	return ratio(m[class][class], actual)
+}
+
+// ratio is n / total, 0 when total is 0
+func ratio(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
+
+// String prints the matrix as a table with the true classes on the rows
+func (m ConfusionMatrix) String() string {
+	var sb strings.Builder
+	sb.WriteString("true\\pred")
+	for j := range m {
+		fmt.Fprintf(&sb, " %6d", j)
+	}
+	sb.WriteString("  recall\n")
+	for i := range m {
+		fmt.Fprintf(&sb, "%9d", i)
+		for _, n := range m[i] {
+			fmt.Fprintf(&sb, " %6d", n)
+		}
+		fmt.Fprintf(&sb, "  %6.3f\n", m.Recall(i))
+	}
+	return sb.String()
+}
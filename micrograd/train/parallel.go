@@ -0,0 +1,55 @@
+package train
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"vdanciu_lang_model/micrograd"
+)
+
+// ForwardParallel runs model.Forward on every input on a pool of workers
+// goroutines (GOMAXPROCS when workers <= 0) and returns the outputs in the order
+// of the inputs, so the loss built from them is the same graph, with the same
+// gradients, as when the examples are run one after another.
+//
+// Building graphs concurrently is safe because the engine ops only read their
+// inputs: the parameters shared by the per example graphs are never written
+// while the graphs are built. Everything that writes to the values, Backward,
+// Gradients, Compile, Tape and the optimizers, must run on a single goroutine
+// once the outputs are merged. The model must compute every example on
+// its own, which rules out MLPs training with dropout or batch norm (see
+// micrograd.MLP.Compilable).
+func ForwardParallel(model Model, inputs [][]*micrograd.Value, workers int) [][]*micrograd.Value {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	outputs := make([][]*micrograd.Value, len(inputs))
+	var next atomic.Int64
+	var failure atomic.Value
+	var wg sync.WaitGroup
+	for w := 0; w < min(workers, len(inputs)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// hand a panic over to the caller instead of crashing the program
+			defer func() {
+				if r := recover(); r != nil {
+					failure.CompareAndSwap(nil, panicValue{r})
+				}
+			}()
+			for i := int(next.Add(1) - 1); i < len(inputs); i = int(next.Add(1) - 1) {
+				outputs[i] = model.Forward(inputs[i])
+			}
+		}()
+	}
+	wg.Wait()
+	if p, ok := failure.Load().(panicValue); ok {
+		panic(p.value)
+	}
+	return outputs
+}
+
+// panicValue wraps recovered values so that atomic.Value always stores the same type
+type panicValue struct {
+	value any
+}
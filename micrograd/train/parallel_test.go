@@ -0,0 +1,100 @@
+package train
+
+import (
+	"testing"
+	"vdanciu_lang_model/micrograd"
+	"vdanciu_lang_model/micrograd/losses"
+	"vdanciu_lang_model/micrograd/optim"
+)
+
+func TestForwardParallelMatchesSequential(t *testing.T) {
+	data := separable(64)
+	mlp := micrograd.NewMLP(2, []int{8, 8, 1}, micrograd.WithSeed(3))
+	loss := ScalarLoss(losses.BCEWithLogits)
+	grads := func(outputs [][]*micrograd.Value) (float64, []float64) {
+		mlp.ZeroGrad()
+		l := loss(outputs, data.Y)
+		l.Backward()
+		out := make([]float64, 0)
+		for _, p := range mlp.Parameters() {
+			out = append(out, p.Grad)
+		}
+		return l.Data, out
+	}
+
+	wantLoss, want := grads(forward(mlp, data.X, micrograd.NewValue, 0))
+	for _, workers := range []int{0, 2, 7, 100} {
+		inputs := make([][]*micrograd.Value, data.Len())
+		for i, row := range data.X {
+			inputs[i] = []*micrograd.Value{micrograd.NewValue(row[0]), micrograd.NewValue(row[1])}
+		}
+		gotLoss, got := grads(ForwardParallel(mlp, inputs, workers))
+		if gotLoss != wantLoss {
+			t.Errorf("workers=%d: loss %v, want %v", workers, gotLoss, wantLoss)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("workers=%d: parameter %d grad %v, want %v", workers, i, got[i], want[i])
+				break
+			}
+		}
+	}
+}
+
+func TestFitWithWorkers(t *testing.T) {
+	fit := func(workers int) []Logs {
+		mlp := micrograd.NewMLP(2, []int{6, 1}, micrograd.WithSeed(4))
+		opt := optim.NewAdam(mlp.Parameters(), optim.DefaultAdamConfig(0.05))
+		trainer := New(mlp, ScalarLoss(losses.Hinge), opt, Config{Epochs: 5, BatchSize: 8, Shuffle: true, ValidationSplit: 0.25, Workers: workers})
+		trainer.Metrics["accuracy"] = BinaryAccuracy
+		return trainer.Fit(separable(50))
+	}
+	sequential, parallel := fit(0), fit(4)
+	for epoch := range sequential {
+		for key, want := range sequential[epoch] {
+			if got := parallel[epoch][key]; got != want {
+				t.Errorf("epoch %d %s: %v with workers, %v without", epoch, key, got, want)
+			}
+		}
+	}
+}
+
+func TestForwardParallelPanics(t *testing.T) {
+	mlp := micrograd.NewMLP(2, []int{1})
+	inputs := [][]*micrograd.Value{{micrograd.NewValue(1), micrograd.NewValue(2)}, {micrograd.NewValue(1)}}
+	defer func() {
+		if r := recover(); r != "input size mismatch" {
+			t.Errorf("recovered %v, want the panic of the worker", r)
+		}
+	}()
+	ForwardParallel(mlp, inputs, 2)
+}
+
+func TestPredictDuringFit(t *testing.T) {
+	fit := func(other *micrograd.MLP) []Logs {
+		mlp := micrograd.NewMLP(2, []int{6, 1}, micrograd.WithSeed(4))
+		opt := optim.NewSGD(mlp.Parameters(), optim.SGDConfig{LR: 0.1})
+		trainer := New(mlp, ScalarLoss(losses.Hinge), opt, Config{Epochs: 5, BatchSize: 8, Workers: 2})
+		done := make(chan struct{})
+		if other != nil {
+			// inference with another model must not affect training
+			go func() {
+				defer close(done)
+				for i := 0; i < 50; i++ {
+					Predict(other, separable(10).X)
+				}
+			}()
+		} else {
+			close(done)
+		}
+		history := trainer.Fit(separable(40))
+		<-done
+		return history
+	}
+	alone, concurrent := fit(nil), fit(micrograd.NewMLP(2, []int{4, 1}, micrograd.WithSeed(5)))
+	for epoch := range alone {
+		if got, want := concurrent[epoch]["loss"], alone[epoch]["loss"]; got != want {
+			t.Errorf("epoch %d: loss %v with a concurrent Predict, %v without", epoch, got, want)
+		}
+	}
+}
@@ -0,0 +1,310 @@
+// Package train runs the usual training loop for micrograd models: forward,
+// loss, ZeroGrad, Backward and an optimizer Step, over epochs and minibatches,
+// with validation, callbacks and early stopping.
+package train
+
+import (
+	"math/rand"
+	"vdanciu_lang_model/micrograd"
+	"vdanciu_lang_model/micrograd/losses"
+	"vdanciu_lang_model/micrograd/optim"
+	"vdanciu_lang_model/micrograd/schedule"
+)
+
+// Model is anything that maps inputs to outputs through trainable parameters, like micrograd.MLP
+type Model interface {
+	Forward(x []*micrograd.Value) []*micrograd.Value
+	Parameters() []*micrograd.Value
+}
+
+// BatchModel is a Model that can run a whole batch at once, which models with
+// batch normalization need to train. The trainer uses ForwardBatch when it is there.
+type BatchModel interface {
+	Model
+	ForwardBatch(x [][]*micrograd.Value) [][]*micrograd.Value
+}
+
+// ModeModel is a Model that behaves differently while training and evaluating,
+// like a network with dropout. The trainer switches it to training mode for
+// the training epochs and to evaluation mode in Evaluate.
+type ModeModel interface {
+	Model
+	SetTraining(training bool)
+	Training() bool
+}
+
+// LossFunc turns the outputs of a batch and their targets into a single loss value
+type LossFunc func(outputs [][]*micrograd.Value, targets []float64) *micrograd.Value
+
+// ScalarLoss adapts a loss from the losses package to the first output of the model
+func ScalarLoss(fn func([]*micrograd.Value, []float64, ...losses.Option) *micrograd.Value, opts ...losses.Option) LossFunc {
+	return func(outputs [][]*micrograd.Value, targets []float64) *micrograd.Value {
+		preds := make([]*micrograd.Value, len(outputs))
+		for i := range outputs {
+			preds[i] = outputs[i][0]
+		}
+		return fn(preds, targets, opts...)
+	}
+}
+
+// ClassLoss adapts a classification loss from the losses package, like
+// losses.SoftmaxCrossEntropy, to models with one output logit per class and
+// targets holding the class indices
+func ClassLoss(fn func([][]*micrograd.Value, []int, ...losses.Option) *micrograd.Value, opts ...losses.Option) LossFunc {
+	return func(outputs [][]*micrograd.Value, targets []float64) *micrograd.Value {
+		labels := make([]int, len(targets))
+		for i, y := range targets {
+			labels[i] = int(y)
+		}
+		return fn(outputs, labels, opts...)
+	}
+}
+
+// Logs holds the values recorded for an epoch: "loss", every metric by name,
+// "lr", and the same keys with a "val_" prefix for the validation set
+type Logs map[string]float64
+
+type Config struct {
+	Epochs int
+	// BatchSize is the number of examples per optimizer step, 0 means the whole
+	// dataset. A BatchModel gets a last single example together with the batch
+	// before, since batch statistics need at least two examples.
+	BatchSize int
+	// Shuffle reorders the training examples at the start of every epoch
+	Shuffle bool
+	// ValidationSplit is the fraction of the data held out for validation when
+	// Fit is not given a validation set
+	ValidationSplit float64
+	// Seed drives shuffling and the validation split
+	Seed int64
+	// Compile records the loss graph on the first epoch and replays it as a
+	// micrograd.Tape afterwards. It only applies when every epoch is a single
+	// unshuffled batch, where the graph is the same from one epoch to the next,
+	// and to models whose graph does not change in training mode (see
+	// micrograd.MLP.Compilable). The loss must not build its graph from the Data
+	// of the outputs either, see micrograd.Compile.
+	Compile bool
+	// Workers builds the graphs of the examples of a batch on that many
+	// goroutines (see ForwardParallel), 0 or 1 builds them one after another.
+	// The results are the same either way. Models that are not compilable
+	// always run one example after another.
+	Workers int
+}
+
+type Trainer struct {
+	Model     Model
+	Loss      LossFunc
+	Optimizer optim.Optimizer
+	// Schedule, if set, updates the learning rate at the start of every epoch
+	Schedule  schedule.Schedule
+	Metrics   map[string]Metric
+	Callbacks []Callback
+	Config    Config
+
+	rng  *rand.Rand
+	stop bool
+	// step counts the optimizer steps since the start of Fit
+	step int
+	// the compiled loss graph, see Config.Compile
+	tape        *micrograd.Tape
+	tapeLoss    *micrograd.Value
+	tapeOutputs [][]*micrograd.Value
+}
+
+func New(model Model, loss LossFunc, optimizer optim.Optimizer, cfg Config) *Trainer {
+	return &Trainer{
+		Model:     model,
+		Loss:      loss,
+		Optimizer: optimizer,
+		Metrics:   map[string]Metric{},
+		Config:    cfg,
+	}
+}
+
+// Stop ends training after the current epoch, callbacks use it for early stopping
+func (t *Trainer) Stop() {
+	t.stop = true
+}
+
+// Fit trains the model on data and returns the logs of every epoch. The optional
+// validation set is evaluated after every epoch; without it Config.ValidationSplit
+// of data is held out instead.
+func (t *Trainer) Fit(data Dataset, validation ...Dataset) []Logs {
+	t.rng = rand.New(rand.NewSource(t.Config.Seed))
+	t.stop = false
+	t.step = 0
+	t.tape = nil
+
+	var val Dataset
+	if len(validation) > 0 {
+		val = validation[0]
+	} else if t.Config.ValidationSplit > 0 {
+		data, val = data.Split(t.Config.ValidationSplit, t.rng)
+	}
+
+	for _, cb := range t.Callbacks {
+		if b, ok := cb.(TrainBeginCallback); ok {
+			b.OnTrainBegin(t)
+		}
+	}
+
+	history := []Logs{}
+	for epoch := 0; epoch < t.Config.Epochs && !t.stop; epoch++ {
+		if t.Schedule != nil {
+			t.Optimizer.SetLR(t.Schedule.LR(epoch))
+		}
+		logs := t.trainEpoch(data)
+		logs["lr"] = t.Optimizer.LR()
+		if val.Len() > 0 {
+			for name, value := range t.Evaluate(val) {
+				logs["val_"+name] = value
+			}
+		}
+		for _, cb := range t.Callbacks {
+			cb.OnEpochEnd(t, epoch, logs)
+		}
+		history = append(history, logs)
+	}
+	return history
+}
+
+// Evaluate computes the loss and the metrics over the whole dataset without
+// training, in inference mode (see micrograd.NoGradValue)
+func (t *Trainer) Evaluate(data Dataset) Logs {
+	outputs := predict(t.Model, data.X, t.Config.Workers)
+	logs := Logs{"loss": t.Loss(outputs, data.Y).Data}
+	for name, metric := range t.Metrics {
+		logs[name] = metric(outputs, data.Y)
+	}
+	return logs
+}
+
+func (t *Trainer) trainEpoch(data Dataset) Logs {
+	if m, ok := t.Model.(ModeModel); ok {
+		m.SetTraining(true)
+	}
+	if t.Config.Compile && !t.Config.Shuffle && (t.Config.BatchSize <= 0 || t.Config.BatchSize >= data.Len()) && compilable(t.Model) {
+		return t.trainCompiledEpoch(data)
+	}
+	indices := make([]int, data.Len())
+	for i := range indices {
+		indices[i] = i
+	}
+	if t.Config.Shuffle {
+		t.rng.Shuffle(len(indices), func(i, j int) { indices[i], indices[j] = indices[j], indices[i] })
+	}
+	batchSize := t.Config.BatchSize
+	if batchSize <= 0 {
+		batchSize = data.Len()
+	}
+
+	// average the batch loss and metrics weighted by the batch sizes
+	logs := Logs{}
+	_, batchModel := t.Model.(BatchModel)
+	for start, end := 0, 0; start < len(indices); start = end {
+		end = min(start+batchSize, len(indices))
+		if batchModel && len(indices)-end == 1 {
+			end++
+		}
+		batch := data.Subset(indices[start:end])
+		outputs := t.forward(batch)
+		loss := t.Loss(outputs, batch.Y)
+
+		t.Optimizer.ZeroGrad()
+		loss.Backward()
+		t.onStep(loss)
+		t.Optimizer.Step()
+
+		weight := float64(batch.Len()) / float64(data.Len())
+		logs["loss"] += loss.Data * weight
+		for name, metric := range t.Metrics {
+			logs[name] += metric(outputs, batch.Y) * weight
+		}
+	}
+	return logs
+}
+
+// trainCompiledEpoch runs a full batch epoch on the tape recorded from the first epoch
+func (t *Trainer) trainCompiledEpoch(data Dataset) Logs {
+	if t.tape == nil {
+		t.tapeOutputs = t.forward(data)
+		t.tapeLoss = t.Loss(t.tapeOutputs, data.Y)
+		tape, err := micrograd.Compile(t.tapeLoss)
+		if err != nil {
+			panic(err)
+		}
+		t.tape = tape
+	}
+	t.tape.Forward()
+
+	t.Optimizer.ZeroGrad()
+	t.tape.Backward()
+	t.onStep(t.tapeLoss)
+	t.Optimizer.Step()
+
+	// the tape stores its results in the traced values, the metrics can read them as usual
+	logs := Logs{"loss": t.tapeLoss.Data}
+	for name, metric := range t.Metrics {
+		logs[name] = metric(t.tapeOutputs, data.Y)
+	}
+	return logs
+}
+
+// onStep notifies the StepCallbacks of the step that just ran Backward on loss
+func (t *Trainer) onStep(loss *micrograd.Value) {
+	for _, cb := range t.Callbacks {
+		if s, ok := cb.(StepCallback); ok {
+			s.OnStep(t, t.step, loss)
+		}
+	}
+	t.step++
+}
+
+// compilable tells if the graph the model builds for an example depends only on
+// the example and the parameters, so that it can be recorded once and replayed
+// or built concurrently with the graphs of other examples
+func compilable(model Model) bool {
+	c, ok := model.(interface{ Compilable() bool })
+	return !ok || c.Compilable()
+}
+
+// Predict runs the model on every row of x in inference mode, without recording
+// the graph (see micrograd.NoGradValue) and with the model in evaluation mode
+// (see ModeModel)
+func Predict(model Model, x [][]float64) [][]*micrograd.Value {
+	return predict(model, x, 0)
+}
+
+func predict(model Model, x [][]float64, workers int) [][]*micrograd.Value {
+	if m, ok := model.(ModeModel); ok {
+		defer m.SetTraining(m.Training())
+		m.SetTraining(false)
+	}
+	return forward(model, x, micrograd.NoGradValue, workers)
+}
+
+func (t *Trainer) forward(data Dataset) [][]*micrograd.Value {
+	return forward(t.Model, data.X, micrograd.NewValue, t.Config.Workers)
+}
+
+// forward runs the model on every row of x, making the inputs with newValue
+func forward(model Model, x [][]float64, newValue func(float64) *micrograd.Value, workers int) [][]*micrograd.Value {
+	inputs := make([][]*micrograd.Value, len(x))
+	for i, row := range x {
+		inputs[i] = make([]*micrograd.Value, len(row))
+		for j := range row {
+			inputs[i][j] = newValue(row[j])
+		}
+	}
+	if workers > 1 && compilable(model) {
+		return ForwardParallel(model, inputs, workers)
+	}
+	if m, ok := model.(BatchModel); ok {
+		return m.ForwardBatch(inputs)
+	}
+	outputs := make([][]*micrograd.Value, len(inputs))
+	for i, in := range inputs {
+		outputs[i] = model.Forward(in)
+	}
+	return outputs
+}
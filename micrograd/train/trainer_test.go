@@ -0,0 +1,296 @@
+package train
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"math/rand"
+	"path/filepath"
+	"strings"
+	"testing"
+	"vdanciu_lang_model/micrograd"
+	"vdanciu_lang_model/micrograd/datasets"
+	"vdanciu_lang_model/micrograd/losses"
+	"vdanciu_lang_model/micrograd/optim"
+	"vdanciu_lang_model/micrograd/schedule"
+)
+
+// separable returns points labelled by the sign of x0 + x1
+func separable(n int) Dataset {
+	r := rand.New(rand.NewSource(1))
+	d := Dataset{}
+	for i := 0; i < n; i++ {
+		x := []float64{r.Float64()*2 - 1, r.Float64()*2 - 1}
+		y := -1.0
+		if x[0]+x[1] > 0 {
+			y = 1.0
+		}
+		d.X = append(d.X, x)
+		d.Y = append(d.Y, y)
+	}
+	return d
+}
+
+func TestFitLearnsSeparableData(t *testing.T) {
+	mlp := micrograd.NewMLP(2, []int{4, 1}, micrograd.WithSeed(1))
+	opt := optim.NewAdam(mlp.Parameters(), optim.DefaultAdamConfig(0.05))
+	trainer := New(mlp, ScalarLoss(losses.Hinge), opt, Config{Epochs: 30, BatchSize: 16, Shuffle: true, ValidationSplit: 0.2})
+	trainer.Metrics["accuracy"] = BinaryAccuracy
+	var buf bytes.Buffer
+	trainer.Callbacks = append(trainer.Callbacks, Logger{W: &buf, Every: 10})
+
+	history := trainer.Fit(separable(100))
+	if len(history) != 30 {
+		t.Fatalf("got %d epochs, want 30", len(history))
+	}
+	last := history[len(history)-1]
+	if last["val_accuracy"] < 0.9 {
+		t.Errorf("validation accuracy %v, want at least 0.9", last["val_accuracy"])
+	}
+	if last["loss"] >= history[0]["loss"] {
+		t.Errorf("loss did not go down: %v -> %v", history[0]["loss"], last["loss"])
+	}
+	if n := strings.Count(buf.String(), "epoch "); n != 3 {
+		t.Errorf("logger printed %d lines, want 3:\n%s", n, buf.String())
+	}
+}
+
+func TestScheduleSetsLearningRate(t *testing.T) {
+	mlp := micrograd.NewMLP(2, []int{1}, micrograd.WithSeed(1))
+	opt := optim.NewSGD(mlp.Parameters(), optim.SGDConfig{})
+	trainer := New(mlp, ScalarLoss(losses.MSE), opt, Config{Epochs: 3})
+	trainer.Schedule = schedule.Exponential{Base: 1, Gamma: 0.5}
+	history := trainer.Fit(separable(10))
+	for epoch, logs := range history {
+		if want := math.Pow(0.5, float64(epoch)); logs["lr"] != want {
+			t.Errorf("epoch %d: lr %v, want %v", epoch, logs["lr"], want)
+		}
+	}
+}
+
+func TestEarlyStopping(t *testing.T) {
+	mlp := micrograd.NewMLP(2, []int{1}, micrograd.WithSeed(1))
+	// a zero learning rate never improves the validation loss
+	opt := optim.NewSGD(mlp.Parameters(), optim.SGDConfig{LR: 0})
+	stopper := &EarlyStopping{Monitor: "val_loss", Patience: 3}
+	trainer := New(mlp, ScalarLoss(losses.MSE), opt, Config{Epochs: 50})
+	trainer.Callbacks = append(trainer.Callbacks, stopper)
+	data := separable(20)
+
+	history := trainer.Fit(data, data)
+	if len(history) != 4 || stopper.StoppedEpoch != 3 {
+		t.Errorf("stopped after %d epochs at epoch %d, want 4 epochs and epoch 3", len(history), stopper.StoppedEpoch)
+	}
+	// the callback must start over on the next Fit
+	if history = trainer.Fit(data, data); len(history) != 4 {
+		t.Errorf("second fit ran %d epochs, want 4", len(history))
+	}
+}
+
+func TestEarlyStoppingMissingMonitor(t *testing.T) {
+	mlp := micrograd.NewMLP(2, []int{1}, micrograd.WithSeed(1))
+	opt := optim.NewSGD(mlp.Parameters(), optim.SGDConfig{LR: 0})
+	stopper := &EarlyStopping{Monitor: "val_loss", Patience: 3}
+	trainer := New(mlp, ScalarLoss(losses.MSE), opt, Config{Epochs: 10})
+	trainer.Callbacks = append(trainer.Callbacks, stopper)
+
+	// without a validation set there is no val_loss to monitor
+	history := trainer.Fit(separable(20))
+	if len(history) != 10 || stopper.StoppedEpoch != -1 {
+		t.Errorf("stopped after %d epochs at epoch %d, want 10 epochs and no stop", len(history), stopper.StoppedEpoch)
+	}
+	if stopper.Err == nil {
+		t.Error("expected an error for the missing val_loss")
+	}
+}
+
+func TestCheckpoint(t *testing.T) {
+	mlp := micrograd.NewMLP(2, []int{3, 1}, micrograd.WithSeed(1))
+	opt := optim.NewSGD(mlp.Parameters(), optim.SGDConfig{LR: 0.1})
+	dir := t.TempDir()
+	checkpoint := &Checkpoint{Path: filepath.Join(dir, "epoch%d.json")}
+	trainer := New(mlp, ScalarLoss(losses.Hinge), opt, Config{Epochs: 2})
+	trainer.Callbacks = append(trainer.Callbacks, checkpoint)
+	trainer.Fit(separable(20))
+	if checkpoint.Err != nil {
+		t.Fatal(checkpoint.Err)
+	}
+	for _, name := range []string{"epoch0.json", "epoch1.json"} {
+		if _, err := micrograd.LoadMLP(filepath.Join(dir, name)); err != nil {
+			t.Errorf("loading %s: %v", name, err)
+		}
+	}
+}
+
+func TestCompiledFitMatchesDynamic(t *testing.T) {
+	blobs := datasets.Blobs(datasets.Config{Samples: 30, Noise: 0.5, Classes: 3, Seed: 2})
+	tests := []struct {
+		name   string
+		data   Dataset
+		nouts  []int
+		loss   func(params []*micrograd.Value) LossFunc
+		metric Metric
+	}{
+		{"hinge", separable(30), []int{4, 1}, func(params []*micrograd.Value) LossFunc {
+			return ScalarLoss(losses.Hinge, losses.WithL2(1e-3, params))
+		}, BinaryAccuracy},
+		// the shift of the softmax follows the logits on the tape
+		{"softmax ce", Dataset{X: blobs.X, Y: blobs.Targets()}, []int{4, 3}, func(params []*micrograd.Value) LossFunc {
+			return ClassLoss(losses.SoftmaxCrossEntropy, losses.WithL2(1e-3, params))
+		}, Accuracy},
+	}
+	for _, tt := range tests {
+		fit := func(compile bool) []Logs {
+			mlp := micrograd.NewMLP(2, tt.nouts, micrograd.WithSeed(2))
+			opt := optim.NewSGD(mlp.Parameters(), optim.SGDConfig{LR: 0.3, Momentum: 0.5})
+			trainer := New(mlp, tt.loss(mlp.Parameters()), opt, Config{Epochs: 10, Compile: compile})
+			trainer.Metrics["accuracy"] = tt.metric
+			return trainer.Fit(tt.data)
+		}
+		dynamic, compiled := fit(false), fit(true)
+		for epoch := range dynamic {
+			for key, want := range dynamic[epoch] {
+				if got := compiled[epoch][key]; got != want {
+					t.Errorf("%s epoch %d %s: compiled %v, dynamic %v", tt.name, epoch, key, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestFitRegularizedModel(t *testing.T) {
+	mlp := micrograd.NewMLP(2, []int{8, 1}, micrograd.WithSeed(1), micrograd.WithBatchNorm(), micrograd.WithDropout(0.1))
+	opt := optim.NewAdam(mlp.Parameters(), optim.DefaultAdamConfig(0.05))
+	trainer := New(mlp, ScalarLoss(losses.Hinge), opt, Config{Epochs: 30, BatchSize: 16, Shuffle: true, Seed: 1, Compile: true})
+	trainer.Metrics["accuracy"] = BinaryAccuracy
+	data := separable(100)
+
+	history := trainer.Fit(data, data)
+	if acc := history[len(history)-1]["val_accuracy"]; acc < 0.9 {
+		t.Errorf("validation accuracy %v, want at least 0.9", acc)
+	}
+	if !mlp.Training() {
+		t.Error("Evaluate left the model in evaluation mode")
+	}
+}
+
+func TestFitBatchNormUnevenBatches(t *testing.T) {
+	// 97 examples in batches of 16 leave a single example at the end
+	mlp := micrograd.NewMLP(2, []int{4, 1}, micrograd.WithSeed(1), micrograd.WithBatchNorm())
+	opt := optim.NewSGD(mlp.Parameters(), optim.SGDConfig{LR: 0.1})
+	var steps int
+	trainer := New(mlp, ScalarLoss(losses.Hinge), opt, Config{Epochs: 2, BatchSize: 16})
+	trainer.Callbacks = []Callback{stepCounter{&steps}}
+	history := trainer.Fit(separable(97))
+	if len(history) != 2 || steps != 12 {
+		t.Errorf("ran %d epochs of %d steps, want 2 epochs of 6 steps", len(history), steps/max(len(history), 1))
+	}
+}
+
+// stepCounter counts the optimizer steps
+type stepCounter struct {
+	steps *int
+}
+
+func (c stepCounter) OnEpochEnd(*Trainer, int, Logs) {}
+
+func (c stepCounter) OnStep(*Trainer, int, *micrograd.Value) {
+	*c.steps++
+}
+
+func TestFitMultiClass(t *testing.T) {
+	blobs := datasets.Blobs(datasets.Config{Samples: 90, Noise: 0.5, Classes: 3, Seed: 1})
+	data := Dataset{X: blobs.X, Y: blobs.Targets()}
+	mlp := micrograd.NewMLP(2, []int{8, 3}, micrograd.WithSeed(1))
+	opt := optim.NewAdam(mlp.Parameters(), optim.DefaultAdamConfig(0.05))
+	trainer := New(mlp, ClassLoss(losses.SoftmaxCrossEntropy), opt, Config{Epochs: 50, Compile: true})
+	trainer.Metrics["accuracy"] = Accuracy
+
+	history := trainer.Fit(data)
+	if acc := history[len(history)-1]["accuracy"]; acc < 0.95 {
+		t.Errorf("accuracy %v, want at least 0.95", acc)
+	}
+	matrix := NewConfusionMatrix(Predict(mlp, data.X), data.Y, 3)
+	if matrix.Accuracy() != trainer.Evaluate(data)["accuracy"] {
+		t.Errorf("confusion matrix accuracy %v, Evaluate %v", matrix.Accuracy(), trainer.Evaluate(data)["accuracy"])
+	}
+}
+
+func TestConfusionMatrix(t *testing.T) {
+	logits := func(l ...float64) []*micrograd.Value {
+		out := make([]*micrograd.Value, len(l))
+		for i := range l {
+			out[i] = micrograd.NewValue(l[i])
+		}
+		return out
+	}
+	outputs := [][]*micrograd.Value{
+		logits(2, 1, 0), logits(0, 3, 1), logits(0, 1, 2), logits(5, 1, 2), logits(1, 1, 0),
+	}
+	targets := []float64{0, 1, 2, 2, 1}
+	m := NewConfusionMatrix(outputs, targets, 3)
+	want := ConfusionMatrix{{1, 0, 0}, {1, 1, 0}, {1, 0, 1}}
+	for i := range want {
+		for j := range want[i] {
+			if m[i][j] != want[i][j] {
+				t.Fatalf("got %v, want %v", m, want)
+			}
+		}
+	}
+	if m.Accuracy() != Accuracy(outputs, targets) || m.Accuracy() != 0.6 {
+		t.Errorf("accuracy %v, metric %v, want 0.6", m.Accuracy(), Accuracy(outputs, targets))
+	}
+	if m.Precision(0) != 1.0/3 || m.Recall(2) != 0.5 {
+		t.Errorf("precision(0) %v recall(2) %v, want 1/3 and 1/2", m.Precision(0), m.Recall(2))
+	}
+	if !strings.Contains(m.String(), "recall") {
+		t.Errorf("unexpected table:\n%s", m)
+	}
+}
+
+func TestConfusionMatrixEmptyClass(t *testing.T) {
+	// class 2 has no examples and is never predicted
+	outputs := [][]*micrograd.Value{
+		{micrograd.NewValue(1), micrograd.NewValue(0), micrograd.NewValue(0)},
+		{micrograd.NewValue(0), micrograd.NewValue(1), micrograd.NewValue(0)},
+	}
+	m := NewConfusionMatrix(outputs, []float64{0, 1}, 3)
+	if m.Precision(2) != 0 || m.Recall(2) != 0 {
+		t.Errorf("precision(2) %v recall(2) %v, want 0 and 0", m.Precision(2), m.Recall(2))
+	}
+	if strings.Contains(m.String(), "NaN") {
+		t.Errorf("table prints NaN:\n%s", m)
+	}
+	if empty := NewConfusionMatrix(nil, nil, 2); empty.Accuracy() != 0 {
+		t.Errorf("accuracy of an empty matrix %v, want 0", empty.Accuracy())
+	}
+}
+
+func TestGraphProfiler(t *testing.T) {
+	mlp := micrograd.NewMLP(2, []int{4, 1}, micrograd.WithSeed(1))
+	opt := optim.NewSGD(mlp.Parameters(), optim.SGDConfig{LR: 0.1})
+	var buf bytes.Buffer
+	profiler := &GraphProfiler{W: &buf, Every: 2, JSON: true}
+	trainer := New(mlp, ScalarLoss(losses.Hinge), opt, Config{Epochs: 3, BatchSize: 10})
+	trainer.Callbacks = []Callback{profiler}
+
+	// 2 steps per epoch, steps 0, 2 and 4 are profiled
+	history := trainer.Fit(separable(20))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d reports, want 3:\n%s", len(lines), buf.String())
+	}
+	var report struct {
+		Step int `json:"step"`
+		micrograd.GraphStats
+	}
+	if err := json.Unmarshal([]byte(lines[2]), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Step != 4 || report.Nodes == 0 || report.Ops["*"] == 0 {
+		t.Errorf("unexpected report %+v", report)
+	}
+	if history[2]["graph_nodes"] != float64(profiler.Last.Nodes) || profiler.Err != nil {
+		t.Errorf("logs %v, last %+v, err %v", history[2], profiler.Last, profiler.Err)
+	}
+}
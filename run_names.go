@@ -7,6 +7,7 @@ import (
 	"os"
 	"sort"
 	"vdanciu_lang_model/micrograd/migete"
+	"vdanciu_lang_model/micrograd/schedule"
 )
 
 // func main() {
@@ -76,11 +77,14 @@ func RunNames() {
 	fmt.Printf("Number of parameters: %v\n", numParameters)
 
 	// hyperparameters
-	//LEARNING_RATE := 0.1
 	NUM_EPOCHS := 100000
 	//PRINT_EVERY := 1000
+	// learning rate 0.1 for the first half of the training, then 0.01
+	lrSchedule := schedule.StepDecay{Base: 0.1, Gamma: 0.1, StepSize: NUM_EPOCHS / 2}
 
-	// training loop
+	// training loop. migete does not backpropagate through cross_entropy and
+	// index yet, so no gradient reaches the parameters and the update below is a
+	// no-op until those backward functions are written.
 	for epoch := 0; epoch < NUM_EPOCHS; epoch++ {
 		// minibatch
 		MINIBATCH_SIZE := 32
@@ -91,6 +95,16 @@ func RunNames() {
 		logits := h.MatMul(W2).Add(b2)
 		loss := logits.CrossEntropy(Ytr.Index(ix))
 		loss.Backward()
+
+		// update, then reset the gradients since backward accumulates into them
+		lr := float32(lrSchedule.LR(epoch))
+		step := migete.NewTensorData[float32](migete.MakeShape(1), []float32{-lr}, true)
+		for _, p := range parameters {
+			if p.Grad != nil {
+				p.Data = p.Data.Add(p.Grad.Mul(step))
+				p.ResetGrad()
+			}
+		}
 	}
 }
 
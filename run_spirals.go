@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"vdanciu_lang_model/micrograd"
+	"vdanciu_lang_model/micrograd/datasets"
+	"vdanciu_lang_model/micrograd/losses"
+	"vdanciu_lang_model/micrograd/optim"
+	"vdanciu_lang_model/micrograd/plotting"
+	"vdanciu_lang_model/micrograd/train"
+)
+
+// runSpirals trains a 3 class classifier on three interleaved spiral arms: the
+// network has one output logit per class, trained with softmax cross-entropy
+func runSpirals() {
+	const classes = 3
+	spirals := datasets.Spirals(datasets.Config{Samples: 150, Noise: 0.02, Classes: classes, Seed: 7})
+	mlp := micrograd.NewMLP(2, []int{16, 16, classes}, micrograd.WithSeed(7),
+		micrograd.WithHiddenActivation(micrograd.Tanh), micrograd.WithInit(micrograd.XavierUniform))
+	fmt.Println(mlp)
+	fmt.Printf("number of parameters: %v\n", len(mlp.Parameters()))
+
+	// the targets hold the class indices 0, 1 and 2
+	data := train.Dataset{X: spirals.X, Y: spirals.Targets()}
+	loss := train.ClassLoss(losses.SoftmaxCrossEntropy, losses.WithL2(1e-4, mlp.Parameters()))
+
+	optimizer := optim.NewAdam(mlp.Parameters(), optim.DefaultAdamConfig(0.05))
+	// the softmax shift is a node of the loss graph, so the compiled tape
+	// follows the logits from one epoch to the next
+	trainer := train.New(mlp, loss, optimizer, train.Config{Epochs: 300, Compile: true})
+	trainer.Metrics["accuracy"] = train.Accuracy
+	trainer.Callbacks = []train.Callback{train.Logger{Every: 25}}
+	trainer.Fit(data)
+
+	// which arms get mistaken for which
+	matrix := train.NewConfusionMatrix(train.Predict(mlp, data.X), data.Y, classes)
+	fmt.Print(matrix)
+	fmt.Printf("accuracy: %v\n", matrix.Accuracy())
+
+	boundary := plotting.BoundaryConfig{Title: "The spirals"}
+	if err := plotting.SaveDecisionBoundary(mlp, data, boundary, "spirals.png"); err != nil {
+		panic(err)
+	}
+}